@@ -0,0 +1,85 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xmidt-org/interpreter"
+)
+
+var eventTypesCmd = &cobra.Command{
+	Use:   "event-types",
+	Short: "Information about the registered event types",
+}
+
+var listEventTypesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registered event types along with their roles and expected order",
+	Run: func(cmd *cobra.Command, args []string) {
+		printEventTypes()
+	},
+}
+
+// EventTypeConfig is the config for an event type that is added to the event type registry.
+type EventTypeConfig struct {
+	Name  string
+	Roles []string
+	Order int
+}
+
+func init() {
+	eventTypesCmd.AddCommand(listEventTypesCmd)
+	rootCmd.AddCommand(eventTypesCmd)
+}
+
+// registerEventTypes adds the event types in the config to the default event type registry.
+func registerEventTypes() {
+	var configs []EventTypeConfig
+	viper.UnmarshalKey("eventTypes", &configs)
+	for _, config := range configs {
+		roles, err := interpreter.ParseRoles(config.Roles...)
+		if err == nil {
+			err = interpreter.DefaultEventTypeRegistry().Register(interpreter.EventTypeInfo{
+				Name:  config.Name,
+				Roles: roles,
+				Order: config.Order,
+			})
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to register event type %s: %v\n", config.Name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func printEventTypes() {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Event Type", "Roles", "Order"})
+	for _, info := range interpreter.DefaultEventTypeRegistry().Types() {
+		table.Append([]string{info.Name, info.Roles.String(), strconv.Itoa(info.Order)})
+	}
+	table.Render()
+}
@@ -0,0 +1,80 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+const explanationSeparator = "\n\n----------------------------------------\n\n"
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <tag|events-file>",
+	Short: "Explain a tag, or the problems found in a json file containing a list of events",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		explain(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
+
+func explain(arg string) {
+	if tag := validation.ParseTag(arg); tag != validation.Unknown || strings.EqualFold(arg, validation.UnknownStr) {
+		fmt.Println(validation.Explain(tag))
+		return
+	}
+
+	events, err := readFile(arg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s is not a known tag or a readable events file: %v\n", arg, err)
+		os.Exit(1)
+	}
+
+	eventValidator, cycleValidator := createValidators()
+	var explanations []string
+	if valid, err := createHistoryValidators().Valid(events); !valid {
+		explanations = append(explanations, fmt.Sprintf("History:\n%s", validation.ExplainError(err)))
+	}
+
+	for _, cycle := range splitIntoCycles(events) {
+		if valid, err := cycleValidator.Valid(cycle.Events); !valid {
+			explanations = append(explanations, fmt.Sprintf("Cycle %s:\n%s", cycle.ID, validation.ExplainError(err)))
+		}
+
+		for _, event := range cycle.Events {
+			if valid, err := eventValidator.Valid(event); !valid {
+				explanations = append(explanations, validation.ExplainError(validation.EventWithError{Event: event, OriginalErr: err}))
+			}
+		}
+	}
+
+	if len(explanations) == 0 {
+		fmt.Println(validation.Explain(validation.Pass))
+		return
+	}
+
+	fmt.Println(strings.Join(explanations, explanationSeparator))
+}
@@ -42,6 +42,46 @@ func getBirthdateString(event interpreter.Event) string {
 	return time.Unix(0, event.Birthdate).UTC().Format(timeFormat)
 }
 
+// errorsForEvent returns the errors in err that were caused by the event, matched by transaction uuid.
+// Errors that do not point to any offending events apply to every event and are always kept.
+func errorsForEvent(err error, event interpreter.Event) error {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		if err == nil || !causedBy(err, event) {
+			return nil
+		}
+		return err
+	}
+
+	var found validation.Errors
+	for _, e := range errs {
+		if e = errorsForEvent(e, event); e != nil {
+			found = append(found, e)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	return found
+}
+
+func causedBy(err error, event interpreter.Event) bool {
+	var errWithEvents validation.ErrorWithEvents
+	if !errors.As(err, &errWithEvents) || len(errWithEvents.Events()) == 0 {
+		return true
+	}
+
+	for _, e := range errWithEvents.Events() {
+		if e.TransactionUUID == event.TransactionUUID {
+			return true
+		}
+	}
+
+	return false
+}
+
 func errorTagsToString(err error) string {
 	if err == nil {
 		return ""
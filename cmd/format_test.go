@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/history"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestErrorsForEvent(t *testing.T) {
+	event1 := interpreter.Event{TransactionUUID: "1"}
+	event2 := interpreter.Event{TransactionUUID: "2"}
+	event3 := interpreter.Event{TransactionUUID: "3"}
+	errWithEvent1 := history.CycleValidationErr{OriginalErr: history.ErrRepeatID, OffendingEvents: []interpreter.Event{event1}}
+	errWithEvent2 := history.CycleValidationErr{OriginalErr: history.ErrFalseReboot, OffendingEvents: []interpreter.Event{event2, event1}}
+	errWithoutEvents := history.CycleValidationErr{OriginalErr: history.ErrNoReboot}
+	plainErr := errors.New("test error")
+
+	tests := []struct {
+		description string
+		err         error
+		event       interpreter.Event
+		expectedErr error
+	}{
+		{
+			description: "no error",
+			event:       event1,
+		},
+		{
+			description: "caused by event",
+			err:         errWithEvent1,
+			event:       event1,
+			expectedErr: errWithEvent1,
+		},
+		{
+			description: "caused by other events",
+			err:         errWithEvent1,
+			event:       event2,
+		},
+		{
+			description: "no offending events",
+			err:         errWithoutEvents,
+			event:       event3,
+			expectedErr: errWithoutEvents,
+		},
+		{
+			description: "error without events",
+			err:         plainErr,
+			event:       event3,
+			expectedErr: plainErr,
+		},
+		{
+			description: "some errors caused by event",
+			err:         validation.Errors{errWithEvent1, errWithEvent2, errWithoutEvents},
+			event:       event2,
+			expectedErr: validation.Errors{errWithEvent2, errWithoutEvents},
+		},
+		{
+			description: "nested errors",
+			err:         validation.Errors{validation.Errors{errWithEvent1, errWithEvent2}, validation.Errors{errWithEvent1}, plainErr},
+			event:       event2,
+			expectedErr: validation.Errors{validation.Errors{errWithEvent2}, plainErr},
+		},
+		{
+			description: "no errors caused by event",
+			err:         validation.Errors{errWithEvent1, validation.Errors{errWithEvent2}},
+			event:       event3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedErr, errorsForEvent(tc.err, tc.event))
+		})
+	}
+}
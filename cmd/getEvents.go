@@ -55,16 +55,19 @@ func getEvents(eventsCallback func([]interpreter.Event)) {
 		eventsCallback(events)
 		os.Exit(0)
 	} else {
-		var config CodexConfig
-		viper.UnmarshalKey("codex", &config)
-		auth, _ := createCodexAuth(config)
-		client := createClient(config, auth)
+		var getDeviceEvents func(string) []interpreter.Event
+		if len(storeDir) > 0 {
+			getDeviceEvents = storeEventsGetter()
+		} else {
+			getDeviceEvents = codexEventsGetter()
+		}
+
 		scanner := bufio.NewScanner(os.Stdin)
 		fmt.Print(prompt)
 		for scanner.Scan() {
 			id := scanner.Text()
 			if len(id) > 0 {
-				events := client.getEvents(id)
+				events := getDeviceEvents(id)
 				eventsCallback(events)
 			}
 			fmt.Print(prompt)
@@ -78,6 +81,26 @@ func getEvents(eventsCallback func([]interpreter.Event)) {
 	}
 }
 
+func codexEventsGetter() func(string) []interpreter.Event {
+	var config CodexConfig
+	viper.UnmarshalKey("codex", &config)
+	auth, _ := createCodexAuth(config)
+	client := createClient(config, auth)
+	return client.getEvents
+}
+
+func storeEventsGetter() func(string) []interpreter.Event {
+	store := openStore()
+	return func(id string) []interpreter.Event {
+		events, err := store.Events(id)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		return events
+	}
+}
+
 func readFile(filePath string) ([]interpreter.Event, error) {
 	var events []interpreter.Event
 	data, err := os.ReadFile(filePath)
@@ -0,0 +1,107 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/history"
+)
+
+var metadataKeys []string
+
+var metadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Print the values of metadata keys in each cycle and every event where they changed",
+	Run: func(cmd *cobra.Command, args []string) {
+		getEvents(printMetadataTimeline)
+	},
+}
+
+func init() {
+	metadataCmd.Flags().StringSliceVarP(&metadataKeys, "keys", "k", nil, "metadata keys to track; defaults to the keys of the metadata validator")
+	rootCmd.AddCommand(metadataCmd)
+	getEventsCmd.AddCommand(metadataCmd)
+}
+
+// timelineKeys returns the metadata keys given with the keys flag, or else the keys of the metadata validator.
+func timelineKeys() []string {
+	if len(metadataKeys) > 0 {
+		return metadataKeys
+	}
+
+	var config ValidatorConfig
+	viper.UnmarshalKey("validators", &config)
+	keys := make([]string, 0, len(config.Metadata))
+	for _, m := range config.Metadata {
+		keys = append(keys, m.Key)
+	}
+
+	return keys
+}
+
+func printMetadataTimeline(events []interpreter.Event) {
+	timeline := history.NewMetadataTimeline(events, timelineKeys(), historyOptions()...)
+
+	cycleTable := tablewriter.NewWriter(os.Stdout)
+	cycleTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	cycleTable.SetHeader([]string{"Cycle ID", "Boot-time", "Key", "Values"})
+	for i, cycle := range timeline.Cycles {
+		// cycle ids match the parse command, where cycle 0 is the newest
+		id := strconv.Itoa(len(timeline.Cycles) - 1 - i)
+		for _, key := range timeline.Keys {
+			if values, found := cycle.Values[key]; found {
+				cycleTable.Append([]string{id, time.Unix(cycle.BootTime, 0).UTC().Format(timeFormat), key, strings.Join(values, "\n")})
+			}
+		}
+	}
+	cycleTable.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	cycleTable.SetRowLine(true)
+	cycleTable.Render()
+
+	if len(timeline.Changes) == 0 {
+		fmt.Println("No metadata changes found")
+		return
+	}
+
+	changeTable := tablewriter.NewWriter(os.Stdout)
+	changeTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	changeTable.SetHeader([]string{"Key", "From", "To", "Event ID", "Birthdate", "Boot-time", "Across Reboot", "Across Session"})
+	for _, change := range timeline.Changes {
+		changeTable.Append([]string{
+			change.Key,
+			change.From,
+			change.To,
+			change.Event.TransactionUUID,
+			getBirthdateString(change.Event),
+			getBoottimeString(change.Event),
+			strconv.FormatBool(change.AcrossReboot),
+			strconv.FormatBool(change.AcrossSession),
+		})
+	}
+	changeTable.SetRowLine(true)
+	changeTable.Render()
+}
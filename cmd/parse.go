@@ -18,6 +18,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 
@@ -27,17 +28,13 @@ import (
 	"github.com/xmidt-org/interpreter/history"
 )
 
-var parser history.EventsParserFunc
+var parser history.EventsParser
 
 var parseCmd = &cobra.Command{
 	Use:   "parse",
 	Short: "Parse list of events into cycles and print",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		if useRebootParser {
-			parser = history.RebootParser(nil)
-		} else {
-			parser = history.CurrentCycleParser(nil)
-		}
+		parser = history.RebootParser(nil, historyOptions()...)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		getEvents(parse)
@@ -58,16 +55,25 @@ func init() {
 
 func parse(events []interpreter.Event) {
 	cycles := parseIntoCycles(events)
-	printBootCycles(cycles)
+	onlineKinds := make(map[string]string)
+	for _, classification := range history.ClassifyOnlineEvents(events, historyOptions()...) {
+		kind := classification.Kind.String()
+		if classification.Reason != nil {
+			kind = fmt.Sprintf("%s: %v", kind, classification.Reason)
+		}
+		onlineKinds[classification.Event.TransactionUUID] = kind
+	}
+
+	printBootCycles(cycles, onlineKinds)
 }
 
-func printBootCycles(cycles []bootCycle) {
+func printBootCycles(cycles []bootCycle, onlineKinds map[string]string) {
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetHeader([]string{"Cycle ID", "Boot-time", "Birthdate", "Destination", "Event ID"})
+	table.SetHeader([]string{"Cycle ID", "Boot-time", "Birthdate", "Destination", "Event ID", "Online Kind"})
 	data := make([][]string, 0, len(cycles))
 	for _, cycle := range cycles {
-		cycleInfo := getCycleInfo(cycle)
+		cycleInfo := getCycleInfo(cycle, onlineKinds)
 		data = append(data, cycleInfo...)
 	}
 
@@ -81,50 +87,39 @@ func printBootCycles(cycles []bootCycle) {
 	table.Render()
 }
 
-func getCycleInfo(cycle bootCycle) [][]string {
+func getCycleInfo(cycle bootCycle, onlineKinds map[string]string) [][]string {
 	cycleInfo := make([][]string, 0, len(cycle.Events))
 	for _, event := range cycle.Events {
-		eventInfo := []string{cycle.ID, getBoottimeString(event), getBirthdateString(event), event.Destination, event.TransactionUUID}
+		eventInfo := []string{cycle.ID, getBoottimeString(event), getBirthdateString(event), event.Destination, event.TransactionUUID, onlineKinds[event.TransactionUUID]}
 		cycleInfo = append(cycleInfo, eventInfo)
 	}
 	return cycleInfo
 }
 
-func parseIntoCycles(events []interpreter.Event) []bootCycle {
-	index := 0
-	var cycles []bootCycle
-	seenBootTimes := make(map[int64]bool)
-	for _, event := range events {
-		if boottime, err := event.BootTime(); err == nil && !seenBootTimes[boottime] {
-			seenBootTimes[boottime] = true
-			parsedEvents, err := parser.Parse(events, event)
-			cycles = append(cycles, bootCycle{
-				ID:     strconv.Itoa(index),
-				Events: parsedEvents,
-				Err:    err,
-			})
-			index++
-		}
+// splitIntoCycles splits the events into boot cycles, sorted from newest to oldest.
+func splitIntoCycles(events []interpreter.Event) []bootCycle {
+	cycles := history.SplitIntoCycles(events, historyOptions()...)
+	bootCycles := make([]bootCycle, 0, len(cycles))
+	for i, cycle := range cycles {
+		bootCycles = append(bootCycles, bootCycle{
+			ID:     strconv.Itoa(i),
+			Events: cycle.NewestToOldest(),
+		})
 	}
 
-	return cycles
+	return bootCycles
 }
 
-func parseByParser(events []interpreter.Event, cycleParser history.EventsParserFunc) []bootCycle {
-	index := 0
-	var cycles []bootCycle
-	seenBootTimes := make(map[int64]bool)
-	for _, event := range events {
-		if boottime, err := event.BootTime(); err == nil && !seenBootTimes[boottime] {
-			seenBootTimes[boottime] = true
-			parsedEvents, err := cycleParser.Parse(events, event)
-			cycles = append(cycles, bootCycle{
-				ID:     strconv.Itoa(index),
-				Events: parsedEvents,
-				Err:    err,
-			})
-			index++
-		}
+// parseIntoCycles splits the events into boot cycles and, if parsing just reboot events,
+// replaces each cycle's events with the events relevant to the reboot that started it.
+func parseIntoCycles(events []interpreter.Event) []bootCycle {
+	cycles := splitIntoCycles(events)
+	if !useRebootParser {
+		return cycles
+	}
+
+	for i, cycle := range cycles {
+		cycles[i].Events, cycles[i].Err = parser.Parse(events, cycle.Events[0])
 	}
 
 	return cycles
@@ -0,0 +1,156 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/xmidt-org/interpreter/history"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+const (
+	eventValidatorKind   = "event"
+	cycleValidatorKind   = "cycle"
+	historyValidatorKind = "history"
+)
+
+var validatorsCmd = &cobra.Command{
+	Use:   "validators",
+	Short: "Information about the registered validators",
+}
+
+var listValidatorsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registered event and cycle validators",
+	Run: func(cmd *cobra.Command, args []string) {
+		printValidators()
+	},
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Information about the tags validators can produce",
+}
+
+var listTagsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all tags and the validators that can produce them",
+	Run: func(cmd *cobra.Command, args []string) {
+		printTags()
+	},
+}
+
+// RegisteredValidatorConfig is the config for a validator that is created by name from a registry.
+type RegisteredValidatorConfig struct {
+	Name   string
+	Config map[string]interface{}
+}
+
+type registeredValidator struct {
+	kind       string
+	descriptor validation.Descriptor
+}
+
+func init() {
+	validatorsCmd.AddCommand(listValidatorsCmd)
+	tagsCmd.AddCommand(listTagsCmd)
+	rootCmd.AddCommand(validatorsCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
+
+func registeredValidators() []registeredValidator {
+	var validators []registeredValidator
+	for _, descriptor := range validation.DefaultRegistry().Descriptors() {
+		validators = append(validators, registeredValidator{kind: eventValidatorKind, descriptor: descriptor})
+	}
+
+	for _, descriptor := range history.DefaultRegistry().Descriptors() {
+		kind := cycleValidatorKind
+		if descriptor.WholeHistory {
+			kind = historyValidatorKind
+		}
+		validators = append(validators, registeredValidator{kind: kind, descriptor: descriptor})
+	}
+
+	return validators
+}
+
+func printValidators() {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Name", "Kind", "Description", "Tags", "Config"})
+	table.SetRowLine(true)
+	for _, validator := range registeredValidators() {
+		descriptor := validator.descriptor
+		configFields := make([]string, 0, len(descriptor.Config))
+		for _, field := range descriptor.Config {
+			configFields = append(configFields, field.Name+" ("+field.Type+"): "+field.Description)
+		}
+
+		table.Append([]string{
+			descriptor.Name,
+			validator.kind,
+			descriptor.Description,
+			strings.Join(validation.TagsToStrings(descriptor.Tags), "\n"),
+			strings.Join(configFields, "\n"),
+		})
+	}
+	table.Render()
+}
+
+func printTags() {
+	producers := make(map[validation.Tag][]string)
+	for _, validator := range registeredValidators() {
+		for _, tag := range validator.descriptor.Tags {
+			producers[tag] = append(producers[tag], validator.kind+": "+validator.descriptor.Name)
+		}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Tag", "Validators"})
+	table.SetRowLine(true)
+	for _, tag := range validation.AllTags() {
+		names := producers[tag]
+		sort.Strings(names)
+		table.Append([]string{tag.String(), strings.Join(names, "\n")})
+	}
+	table.Render()
+}
+
+// configDecoder returns a ConfigDecoder that decodes the config map into a validator's config.
+func configDecoder(config map[string]interface{}) validation.ConfigDecoder {
+	return func(v interface{}) error {
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           v,
+		})
+		if err != nil {
+			return err
+		}
+
+		return decoder.Decode(config)
+	}
+}
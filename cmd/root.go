@@ -22,12 +22,15 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/history"
 )
 
 var (
 	cfgFile         string
 	eventsFile      string
 	useRebootParser bool
+	storeDir        string
 
 	rootCmd = &cobra.Command{
 		Use:   "interpreter",
@@ -38,6 +41,28 @@ var (
 func init() {
 	cobra.OnInitialize(initializeConfig)
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is ./interpreter.yaml)")
+	rootCmd.PersistentFlags().StringVar(&storeDir, "store", "", "directory of a local event store to read events from instead of querying codex")
+	rootCmd.PersistentFlags().Duration("boot-time-tolerance", 0, "how far apart boot-times can be while belonging to the same boot cycle")
+	viper.BindPFlag("bootTimeTolerance", rootCmd.PersistentFlags().Lookup("boot-time-tolerance"))
+}
+
+// historyOptions returns the options used to create parsers and validators from the history package.
+func historyOptions() []history.Option {
+	opts := []history.Option{
+		history.WithBootTimeTolerance(viper.GetDuration("bootTimeTolerance")),
+		history.WithEventTypeRegistry(interpreter.DefaultEventTypeRegistry()),
+	}
+	if viper.IsSet("reboot") {
+		definition := history.DefaultRebootDefinition()
+		viper.UnmarshalKey("reboot", &definition)
+		opts = append(opts, history.WithRebootDefinition(definition))
+	}
+
+	if viper.IsSet("rebootReasonKey") {
+		opts = append(opts, history.WithRebootReasonKey(viper.GetString("rebootReasonKey")))
+	}
+
+	return opts
 }
 
 func initializeConfig() {
@@ -52,4 +77,6 @@ func initializeConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
+
+	registerEventTypes()
 }
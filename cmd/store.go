@@ -0,0 +1,114 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xmidt-org/interpreter/storage"
+)
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Manage the local event store set with --store",
+}
+
+var storeAddCmd = &cobra.Command{
+	Use:   "add <device-id> <events-file>",
+	Short: "Add the events in a json file to a device's history in the local event store",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		addToStore(args[0], args[1])
+	},
+}
+
+var storeCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Remove duplicate events and events outside of the retention limits from the local event store",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		compactStore()
+	},
+}
+
+type StoreConfig struct {
+	MaxEvents        int
+	MaxAge           time.Duration
+	MaxSegmentEvents int
+}
+
+func init() {
+	storeCmd.AddCommand(storeAddCmd)
+	storeCmd.AddCommand(storeCompactCmd)
+	rootCmd.AddCommand(storeCmd)
+}
+
+// openStore opens the local event store in the directory set with --store.
+func openStore() storage.EventStore {
+	if len(storeDir) == 0 {
+		fmt.Fprintln(os.Stderr, "no store directory given; use --store")
+		os.Exit(1)
+	}
+
+	var config StoreConfig
+	viper.UnmarshalKey("store", &config)
+	store, err := storage.NewFileStore(storage.FileStoreConfig{
+		Dir:              storeDir,
+		MaxSegmentEvents: config.MaxSegmentEvents,
+		Retention: storage.Retention{
+			MaxEvents: config.MaxEvents,
+			MaxAge:    config.MaxAge,
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	return store
+}
+
+func addToStore(deviceID string, eventsFile string) {
+	events, err := readFile(eventsFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	store := openStore()
+	defer store.Close()
+	if err := store.Append(deviceID, events...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Added %d events to %s\n", len(events), deviceID)
+}
+
+func compactStore() {
+	store := openStore()
+	defer store.Close()
+	if err := store.Compact(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -32,9 +32,9 @@ import (
 )
 
 var (
-	eventValidator  validation.Validator
-	cycleValidators history.CycleValidator
-	cycleParser     history.EventsParserFunc
+	eventValidator    validation.Validator
+	cycleValidators   history.CycleValidator
+	historyValidators history.CycleValidator
 )
 
 var validateCmd = &cobra.Command{
@@ -42,7 +42,7 @@ var validateCmd = &cobra.Command{
 	Short: "validate a list of cycles and events and print",
 	PreRun: func(cmd *cobra.Command, args []string) {
 		eventValidator, cycleValidators = createValidators()
-		cycleParser = history.CurrentCycleParser(nil)
+		historyValidators = createHistoryValidators()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if useRebootParser {
@@ -60,6 +60,31 @@ type ValidatorConfig struct {
 	BootTimeValidator          TimeValidationConfig
 	BirthdateValidator         TimeValidationConfig
 	EventOrder                 []string
+	Transitions                map[string][]string
+	Sequence                   string
+	Gaps                       GapConfig
+	CheckBootTimeHistory       bool
+	RebootFrequency            history.RebootFrequency
+	CheckSessionIntegrity      bool
+	RebootPolicy               *history.RebootPolicy
+	RebootSchedule             *RebootScheduleConfig
+	MetadataPolicies           []history.MetadataPolicy
+	Firmware                   *FirmwareConfig
+	Registered                 []RegisteredValidatorConfig
+}
+
+type GapConfig struct {
+	MaxGap time.Duration
+	Limits []history.GapLimit
+}
+
+type RebootScheduleConfig struct {
+	Tolerance time.Duration
+}
+
+type FirmwareConfig struct {
+	Patterns []history.FirmwarePattern
+	Rules    []history.FirmwareRule
 }
 
 type MetadataKeyConfig struct {
@@ -86,7 +111,7 @@ func init() {
 }
 
 func validate(events []interpreter.Event) {
-	cycles := parseByParser(events, cycleParser)
+	cycles := splitIntoCycles(events)
 	var allErrors []eventErrs
 	for _, cycle := range cycles {
 		_, cycleErrs := cycleValidators.Valid(cycle.Events)
@@ -102,6 +127,9 @@ func validate(events []interpreter.Event) {
 	}
 
 	printValidationTable(allErrors)
+	if _, err := historyValidators.Valid(events); err != nil {
+		fmt.Printf("History Errors:\n%s\n", errorTagsToString(err))
+	}
 }
 
 func printValidationTable(info []eventErrs) {
@@ -112,7 +140,7 @@ func printValidationTable(info []eventErrs) {
 	for _, eventErr := range info {
 		data = append(data, getValidationRowInfo(eventErr))
 	}
-	table.SetAutoMergeCellsByColumnIndex([]int{0, 2, 5})
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 2})
 	table.SetRowLine(true)
 	table.AppendBulk(data)
 	table.Render()
@@ -125,7 +153,7 @@ func getValidationRowInfo(info eventErrs) []string {
 		getBoottimeString(info.event),
 		info.event.Destination,
 		errorTagsToString(info.eventErrs),
-		errorTagsToString(info.cycleErrs),
+		errorTagsToString(errorsForEvent(info.cycleErrs, info.event)),
 	}
 }
 
@@ -140,10 +168,28 @@ func createValidators() (validation.Validator, history.CycleValidator) {
 func createCycleValidators(config ValidatorConfig) history.CycleValidator {
 	validators := []history.CycleValidator{
 		history.TransactionUUIDValidator(),
-		history.SessionOnlineValidator(func(_ []interpreter.Event, _ string) bool { return false }),
-		history.SessionOfflineValidator(func(_ []interpreter.Event, _ string) bool { return false }),
+		history.SessionOnlineValidator(func(_ []interpreter.Event, _ string) bool { return false }, historyOptions()...),
+		history.SessionOfflineValidator(func(_ []interpreter.Event, _ string) bool { return false }, historyOptions()...),
 		history.EventOrderValidator(config.EventOrder),
 	}
+
+	if len(config.Transitions) > 0 {
+		validators = append(validators, history.StateMachineValidator(config.Transitions))
+	}
+
+	if len(config.Sequence) > 0 {
+		pattern, err := history.ParseSequencePattern(config.Sequence)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to parse sequence: %v\n", err)
+			os.Exit(1)
+		}
+		validators = append(validators, history.SequenceValidator(pattern))
+	}
+
+	if config.Gaps.MaxGap > 0 || len(config.Gaps.Limits) > 0 {
+		validators = append(validators, history.GapValidator(config.Gaps.MaxGap, config.Gaps.Limits))
+	}
+
 	var withinCycleChecks []string
 	var wholeCycleChecks []string
 	for _, metadata := range config.Metadata {
@@ -155,16 +201,102 @@ func createCycleValidators(config ValidatorConfig) history.CycleValidator {
 	}
 
 	if len(withinCycleChecks) > 0 {
-		validators = append(validators, history.MetadataValidator(withinCycleChecks, true))
+		validators = append(validators, history.MetadataValidator(withinCycleChecks, true, historyOptions()...))
 	}
 
 	if len(wholeCycleChecks) > 0 {
-		validators = append(validators, history.MetadataValidator(wholeCycleChecks, false))
+		validators = append(validators, history.MetadataValidator(wholeCycleChecks, false, historyOptions()...))
 	}
 
+	validators = append(validators, registeredCycleValidators(config, false)...)
 	return history.CycleValidators(validators)
 }
 
+// createHistoryValidators creates the validators that are run on a device's whole history rather than on each cycle.
+func createHistoryValidators() history.CycleValidator {
+	var config ValidatorConfig
+	viper.UnmarshalKey("validators", &config)
+	var validators history.CycleValidators
+	if config.CheckBootTimeHistory {
+		validators = append(validators, history.BootTimeValidators(historyOptions()...)...)
+	}
+
+	if config.RebootFrequency.MaxReboots > 0 || config.RebootFrequency.MaxReconnects > 0 {
+		if err := config.RebootFrequency.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid reboot frequency: %v\n", err)
+			os.Exit(1)
+		}
+		validators = append(validators, history.RebootFrequencyValidator(config.RebootFrequency, historyOptions()...))
+	}
+
+	if config.CheckSessionIntegrity {
+		validators = append(validators,
+			history.SessionBootTimeValidator(nil, historyOptions()...),
+			history.SessionOverlapValidator(nil, historyOptions()...),
+			history.SessionEventOrderValidator(nil, historyOptions()...),
+			history.SessionStartValidator(nil, historyOptions()...),
+		)
+	}
+
+	if config.RebootPolicy != nil {
+		validators = append(validators, history.RebootReasonValidator(*config.RebootPolicy, historyOptions()...))
+	}
+
+	if config.RebootSchedule != nil {
+		validators = append(validators, history.RebootScheduleValidator(config.RebootSchedule.Tolerance, historyOptions()...))
+	}
+
+	if len(config.MetadataPolicies) > 0 {
+		for i, policy := range config.MetadataPolicies {
+			scope, err := history.ParseChangeScope(string(policy.Scope))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to parse metadata policy for %s: %v\n", policy.Key, err)
+				os.Exit(1)
+			}
+			config.MetadataPolicies[i].Scope = scope
+		}
+		validators = append(validators, history.MetadataPolicyValidator(config.MetadataPolicies, historyOptions()...))
+	}
+
+	if config.Firmware != nil {
+		parser, err := history.NewFirmwareParser(config.Firmware.Patterns)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create firmware parser: %v\n", err)
+			os.Exit(1)
+		}
+
+		firmwareValidators, err := history.FirmwareValidators(parser, config.Firmware.Rules, historyOptions()...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create firmware validators: %v\n", err)
+			os.Exit(1)
+		}
+		validators = append(validators, firmwareValidators...)
+	}
+
+	return append(validators, registeredCycleValidators(config, true)...)
+}
+
+// registeredCycleValidators creates the registered cycle validators in the config that are either meant
+// to be run on a device's whole history or on each cycle.
+func registeredCycleValidators(config ValidatorConfig, wholeHistory bool) history.CycleValidators {
+	var validators history.CycleValidators
+	for _, registered := range config.Registered {
+		descriptor, found := history.DefaultRegistry().Descriptor(registered.Name)
+		if !found || descriptor.WholeHistory != wholeHistory {
+			continue
+		}
+
+		validator, err := history.DefaultRegistry().New(registered.Name, configDecoder(registered.Config))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create cycle validator %s: %v\n", registered.Name, err)
+			os.Exit(1)
+		}
+		validators = append(validators, validator)
+	}
+
+	return validators
+}
+
 func createEventValidators(config ValidatorConfig) validation.Validator {
 	bootTimeValidator := validation.BootTimeValidator(validation.TimeValidator{
 		Current:      time.Now,
@@ -183,11 +315,40 @@ func createEventValidators(config ValidatorConfig) validation.Validator {
 	birthdateAlignmentValidator := validation.BirthdateAlignmentValidator(config.BirthdateAlignmentDuration)
 	consistentIDValidator := validation.ConsistentDeviceIDValidator()
 	bootDurationValidator := validation.BootDurationValidator(config.MinBootDuration)
-	eventTypeValidator := validation.EventTypeValidator(config.ValidEventTypes)
+	eventTypeValidator := validation.EventTypeRegistryValidator(interpreter.DefaultEventTypeRegistry())
+	if len(config.ValidEventTypes) > 0 {
+		// the fixed list adds to the event types in the registry rather than replacing them
+		registryValidator := eventTypeValidator
+		listValidator := validation.EventTypeValidator(config.ValidEventTypes)
+		eventTypeValidator = func(event interpreter.Event) (bool, error) {
+			if valid, _ := listValidator(event); valid {
+				return true, nil
+			}
+
+			return registryValidator(event)
+		}
+	}
 
 	validators := validation.Validators([]validation.Validator{
 		bootTimeValidator, birthdateValidator, birthdateAlignmentValidator, consistentIDValidator, bootDurationValidator, eventTypeValidator,
 	})
 
+	for _, registered := range config.Registered {
+		if _, found := validation.DefaultRegistry().Descriptor(registered.Name); !found {
+			if _, found = history.DefaultRegistry().Descriptor(registered.Name); !found {
+				fmt.Fprintf(os.Stderr, "validator %s is not registered\n", registered.Name)
+				os.Exit(1)
+			}
+			continue
+		}
+
+		validator, err := validation.DefaultRegistry().New(registered.Name, configDecoder(registered.Config))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create event validator %s: %v\n", registered.Name, err)
+			os.Exit(1)
+		}
+		validators = append(validators, validator)
+	}
+
 	return validators
 }
@@ -18,10 +18,13 @@
 package interpreter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +40,19 @@ const (
 	AuthoritySubexpName = "authority"
 	EventSubexpName     = "event"
 	SchemeSubexpName    = "scheme"
+
+	// Names of the fields that can be ignored by Fingerprint. A single metadata key is
+	// ignored with MetadataFieldPrefix followed by the key, such as "metadata:/boot-time".
+	MsgTypeField        = "msg_type"
+	SourceField         = "source"
+	DestinationField    = "dest"
+	ContentTypeField    = "content_type"
+	MetadataField       = "metadata"
+	MetadataFieldPrefix = "metadata:"
+	PayloadField        = "payload"
+	BirthdateField      = "birth_date"
+	PartnerIDsField     = "partner_ids"
+	SessionIDField      = "sessionID"
 )
 
 var (
@@ -46,6 +62,7 @@ var (
 	ErrBootTimeNotFound = errors.New("boot-time not found")
 	ErrEventRegex       = errors.New("event regex is wrong")
 	ErrTypeNotFound     = errors.New("type not found")
+	ErrRebootSchedule   = errors.New("unable to parse reboot schedule")
 
 	// EventRegex is the regex that an event's destination must match in order to parse the device id properly.
 	EventRegex = regexp.MustCompile(fmt.Sprintf(`^(?P<%s>[^/]+)/(?P<%s>(?P<%s>(?i)mac|uuid|dns|serial):(?P<%s>[^/]+))/(?P<%s>[^/\s]+)`, EventSubexpName, IDSubexpName, SchemeSubexpName, AuthoritySubexpName, TypeSubexpName))
@@ -150,6 +167,92 @@ func (e Event) EventType() (string, error) {
 	return match[index], nil
 }
 
+// RebootSchedule parses the schedule that follows the event type in the destination of a reboot-pending event,
+// such as event:device-status/mac:112233445566/reboot-pending/1612424775/2s, where 1612424775 is the unix time
+// the reboot was scheduled at and 2s is how long after that the device will reboot. A delay without a unit
+// is in seconds. The time the device is expected to reboot is the returned time plus the delay.
+func (e Event) RebootSchedule() (time.Time, time.Duration, error) {
+	match := EventRegex.FindStringIndex(e.Destination)
+	if match == nil {
+		return time.Time{}, 0, ErrRebootSchedule
+	}
+
+	parts := strings.Split(strings.Trim(e.Destination[match[1]:], "/"), "/")
+	if len(parts) < 2 {
+		return time.Time{}, 0, ErrRebootSchedule
+	}
+
+	timestamp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || timestamp <= 0 {
+		return time.Time{}, 0, ErrRebootSchedule
+	}
+
+	delay, err := time.ParseDuration(parts[1])
+	if err != nil {
+		seconds, convErr := strconv.ParseInt(parts[1], 10, 64)
+		if convErr != nil {
+			return time.Time{}, 0, ErrRebootSchedule
+		}
+		delay = time.Duration(seconds) * time.Second
+	}
+
+	if delay < 0 {
+		return time.Time{}, 0, ErrRebootSchedule
+	}
+
+	return time.Unix(timestamp, 0), delay, nil
+}
+
+// Fingerprint returns a hash of the event's content, leaving out the ignored fields. The transaction uuid
+// identifies the event rather than describing its content, so it is never part of the fingerprint, which
+// means that an event retransmitted with a new transaction uuid has the same fingerprint as the original.
+func (e Event) Fingerprint(ignoredFields ...string) string {
+	ignored := make(map[string]bool)
+	for _, field := range ignoredFields {
+		if strings.HasPrefix(field, MetadataFieldPrefix) {
+			field = MetadataFieldPrefix + strings.Trim(strings.TrimPrefix(field, MetadataFieldPrefix), "/")
+		}
+		ignored[field] = true
+	}
+
+	hash := sha256.New()
+	write := func(field string, value string) {
+		if !ignored[field] {
+			// length-prefix each value so that different events cannot write the same bytes
+			fmt.Fprintf(hash, "%s:%d:%s;", field, len(value), value)
+		}
+	}
+
+	write(MsgTypeField, strconv.Itoa(e.MsgType))
+	write(SourceField, e.Source)
+	write(DestinationField, e.Destination)
+	write(ContentTypeField, e.ContentType)
+	write(PayloadField, e.Payload)
+	write(BirthdateField, strconv.FormatInt(e.Birthdate, 10))
+	write(SessionIDField, e.SessionID)
+	for _, partnerID := range e.PartnerIDs {
+		write(PartnerIDsField, partnerID)
+	}
+
+	if !ignored[MetadataField] {
+		keys := make([]string, 0, len(e.Metadata))
+		for key := range e.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			field := MetadataFieldPrefix + strings.Trim(key, "/")
+			if !ignored[field] {
+				write(MetadataFieldPrefix, key)
+				write(field, e.Metadata[key])
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 func getBirthDate(payload []byte) (time.Time, bool) {
 	p := make(map[string]interface{})
 	if len(payload) == 0 {
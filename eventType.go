@@ -0,0 +1,202 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrEmptyEventType = errors.New("event type cannot be empty")
+	ErrUnknownRole    = errors.New("unknown event type role")
+)
+
+// Role describes what an event type means. Roles are flags, so an event type can have several roles.
+type Role int
+
+const (
+	// SessionStartRole marks an event type that starts a device's session, such as online.
+	SessionStartRole Role = 1 << iota
+
+	// SessionEndRole marks an event type that ends a device's session, such as offline.
+	SessionEndRole
+
+	// BootMilestoneRole marks an event type that a device sends as it boots up, such as operational.
+	BootMilestoneRole
+
+	// ShutdownIntentRole marks an event type that a device sends when it is about to shut down, such as reboot-pending.
+	ShutdownIntentRole
+
+	// InformationalRole marks an event type that does not change the state of the device.
+	InformationalRole
+)
+
+var roleNames = []struct {
+	role Role
+	name string
+}{
+	{SessionStartRole, "session-start"},
+	{SessionEndRole, "session-end"},
+	{BootMilestoneRole, "boot-milestone"},
+	{ShutdownIntentRole, "shutdown-intent"},
+	{InformationalRole, "informational"},
+}
+
+// Has returns whether r includes all of the roles passed in.
+func (r Role) Has(role Role) bool {
+	return role != 0 && r&role == role
+}
+
+// String returns the names of the roles separated by "|".
+func (r Role) String() string {
+	var names []string
+	for _, roleName := range roleNames {
+		if r.Has(roleName.role) {
+			names = append(names, roleName.name)
+		}
+	}
+
+	return strings.Join(names, "|")
+}
+
+// ParseRoles combines the roles with the names passed in. Names are case-insensitive.
+func ParseRoles(names ...string) (Role, error) {
+	var roles Role
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, roleName := range roleNames {
+			if roleName.name == name {
+				roles |= roleName.role
+				found = true
+			}
+		}
+
+		if !found {
+			return 0, fmt.Errorf("%w: %s", ErrUnknownRole, name)
+		}
+	}
+
+	return roles, nil
+}
+
+// EventTypeInfo describes an event type.
+type EventTypeInfo struct {
+	Name  string
+	Roles Role
+
+	// Order is the position the event type is expected to appear in within a boot cycle, where event types with
+	// lower orders are expected earlier. Event types with an order of zero do not have an expected position.
+	Order int
+}
+
+// EventTypeRegistry holds the event types that are known and what they mean. It is safe for concurrent use.
+type EventTypeRegistry struct {
+	lock  sync.RWMutex
+	types map[string]EventTypeInfo
+}
+
+var defaultEventTypeRegistry = NewEventTypeRegistry(
+	EventTypeInfo{Name: OnlineEventType, Roles: SessionStartRole | BootMilestoneRole, Order: 1},
+	EventTypeInfo{Name: OperationalEventType, Roles: BootMilestoneRole, Order: 2},
+	EventTypeInfo{Name: FullyManageableEventType, Roles: BootMilestoneRole, Order: 3},
+	EventTypeInfo{Name: RebootPendingEventType, Roles: ShutdownIntentRole, Order: 4},
+	EventTypeInfo{Name: OfflineEventType, Roles: SessionEndRole, Order: 5},
+)
+
+// DefaultEventTypeRegistry returns the registry used when one is not given. It starts out with the
+// online, operational, fully-manageable, reboot-pending and offline event types.
+func DefaultEventTypeRegistry() *EventTypeRegistry {
+	return defaultEventTypeRegistry
+}
+
+// NewEventTypeRegistry creates a registry with the event types passed in. Event types with an empty name are skipped.
+func NewEventTypeRegistry(types ...EventTypeInfo) *EventTypeRegistry {
+	registry := &EventTypeRegistry{types: make(map[string]EventTypeInfo)}
+	for _, info := range types {
+		registry.Register(info)
+	}
+
+	return registry
+}
+
+// Register adds an event type to the registry, replacing any event type with the same name.
+// Names are case-insensitive.
+func (r *EventTypeRegistry) Register(info EventTypeInfo) error {
+	info.Name = strings.TrimSpace(info.Name)
+	if len(info.Name) == 0 {
+		return ErrEmptyEventType
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.types[strings.ToLower(info.Name)] = info
+	return nil
+}
+
+// Lookup returns the event type with the name passed in.
+func (r *EventTypeRegistry) Lookup(name string) (EventTypeInfo, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	info, found := r.types[strings.ToLower(strings.TrimSpace(name))]
+	return info, found
+}
+
+// HasRole returns whether the event type with the name passed in is registered with the role.
+func (r *EventTypeRegistry) HasRole(name string, role Role) bool {
+	info, found := r.Lookup(name)
+	return found && info.Roles.Has(role)
+}
+
+// Types returns all of the event types in the registry, sorted by order and then by name.
+func (r *EventTypeRegistry) Types() []EventTypeInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	types := make([]EventTypeInfo, 0, len(r.types))
+	for _, info := range r.types {
+		types = append(types, info)
+	}
+
+	sort.Slice(types, func(a, b int) bool {
+		if types[a].Order != types[b].Order {
+			return types[a].Order < types[b].Order
+		}
+
+		return types[a].Name < types[b].Name
+	})
+
+	return types
+}
+
+// Names returns the names of the event types with the role passed in, sorted by order and then by name.
+// If role is zero, the names of all event types are returned.
+func (r *EventTypeRegistry) Names(role Role) []string {
+	var names []string
+	for _, info := range r.Types() {
+		if role == 0 || info.Roles.Has(role) {
+			names = append(names, info.Name)
+		}
+	}
+
+	return names
+}
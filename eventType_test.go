@@ -0,0 +1,132 @@
+package interpreter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole(t *testing.T) {
+	tests := []struct {
+		description    string
+		role           Role
+		check          Role
+		expectedHas    bool
+		expectedString string
+	}{
+		{
+			description:    "single role",
+			role:           SessionStartRole,
+			check:          SessionStartRole,
+			expectedHas:    true,
+			expectedString: "session-start",
+		},
+		{
+			description:    "multiple roles",
+			role:           SessionStartRole | BootMilestoneRole,
+			check:          BootMilestoneRole,
+			expectedHas:    true,
+			expectedString: "session-start|boot-milestone",
+		},
+		{
+			description:    "missing role",
+			role:           SessionEndRole,
+			check:          SessionStartRole | SessionEndRole,
+			expectedHas:    false,
+			expectedString: "session-end",
+		},
+		{
+			description: "no roles",
+			role:        0,
+			check:       0,
+			expectedHas: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(tc.expectedHas, tc.role.Has(tc.check))
+			assert.Equal(tc.expectedString, tc.role.String())
+		})
+	}
+}
+
+func TestParseRoles(t *testing.T) {
+	tests := []struct {
+		description string
+		names       []string
+		expected    Role
+		expectedErr error
+	}{
+		{
+			description: "valid",
+			names:       []string{"session-start", " Boot-Milestone "},
+			expected:    SessionStartRole | BootMilestoneRole,
+		},
+		{
+			description: "all roles",
+			names:       []string{"session-start", "session-end", "boot-milestone", "shutdown-intent", "informational"},
+			expected:    SessionStartRole | SessionEndRole | BootMilestoneRole | ShutdownIntentRole | InformationalRole,
+		},
+		{
+			description: "no names",
+		},
+		{
+			description: "unknown role",
+			names:       []string{"session-start", "random"},
+			expectedErr: ErrUnknownRole,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			roles, err := ParseRoles(tc.names...)
+			assert.Equal(tc.expected, roles)
+			assert.True(errors.Is(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestEventTypeRegistry(t *testing.T) {
+	assert := assert.New(t)
+	registry := NewEventTypeRegistry(
+		EventTypeInfo{Name: "online", Roles: SessionStartRole, Order: 1},
+		EventTypeInfo{Name: "offline", Roles: SessionEndRole, Order: 3},
+		EventTypeInfo{Name: ""},
+	)
+
+	assert.True(errors.Is(registry.Register(EventTypeInfo{Name: " "}), ErrEmptyEventType))
+	assert.Nil(registry.Register(EventTypeInfo{Name: "Cloud-Connected", Roles: BootMilestoneRole, Order: 2}))
+	assert.Nil(registry.Register(EventTypeInfo{Name: "heartbeat", Roles: InformationalRole}))
+
+	info, found := registry.Lookup("cloud-connected")
+	assert.True(found)
+	assert.Equal(EventTypeInfo{Name: "Cloud-Connected", Roles: BootMilestoneRole, Order: 2}, info)
+	_, found = registry.Lookup("random")
+	assert.False(found)
+
+	assert.True(registry.HasRole("ONLINE", SessionStartRole))
+	assert.False(registry.HasRole("online", SessionEndRole))
+	assert.False(registry.HasRole("random", SessionStartRole))
+
+	assert.Equal([]string{"heartbeat", "online", "Cloud-Connected", "offline"}, registry.Names(0))
+	assert.Equal([]string{"offline"}, registry.Names(SessionEndRole))
+	assert.Empty(registry.Names(ShutdownIntentRole))
+
+	// registering an event type again replaces it.
+	assert.Nil(registry.Register(EventTypeInfo{Name: "online", Roles: SessionStartRole | BootMilestoneRole, Order: 1}))
+	assert.Equal([]string{"online", "Cloud-Connected"}, registry.Names(BootMilestoneRole))
+	assert.Len(registry.Types(), 4)
+}
+
+func TestDefaultEventTypeRegistry(t *testing.T) {
+	assert := assert.New(t)
+	registry := DefaultEventTypeRegistry()
+	assert.Equal([]string{OnlineEventType}, registry.Names(SessionStartRole))
+	assert.Equal([]string{OfflineEventType}, registry.Names(SessionEndRole))
+	assert.Equal([]string{RebootPendingEventType}, registry.Names(ShutdownIntentRole))
+	assert.Equal([]string{OnlineEventType, OperationalEventType, FullyManageableEventType}, registry.Names(BootMilestoneRole))
+}
@@ -252,6 +252,64 @@ func TestType(t *testing.T) {
 	}
 }
 
+func TestRebootSchedule(t *testing.T) {
+	tests := []struct {
+		destination   string
+		expectedErr   error
+		expectedTime  time.Time
+		expectedDelay time.Duration
+	}{
+		{
+			destination:   "event:device-status/mac:112233445566/reboot-pending/1612424775/2s",
+			expectedTime:  time.Unix(1612424775, 0),
+			expectedDelay: 2 * time.Second,
+		},
+		{
+			destination:   "event:device-status/mac:112233445566/reboot-pending/1612424775/300/extra",
+			expectedTime:  time.Unix(1612424775, 0),
+			expectedDelay: 5 * time.Minute,
+		},
+		{
+			destination: "event:device-status/mac:112233445566/reboot-pending",
+			expectedErr: ErrRebootSchedule,
+		},
+		{
+			destination: "event:device-status/mac:112233445566/reboot-pending/1612424775",
+			expectedErr: ErrRebootSchedule,
+		},
+		{
+			destination: "event:device-status/mac:112233445566/reboot-pending/time/2s",
+			expectedErr: ErrRebootSchedule,
+		},
+		{
+			destination: "event:device-status/mac:112233445566/reboot-pending/1612424775/-2s",
+			expectedErr: ErrRebootSchedule,
+		},
+		{
+			destination: "event:device-status/mac:112233445566/reboot-pending/1612424775/soon",
+			expectedErr: ErrRebootSchedule,
+		},
+		{
+			destination: "some-event",
+			expectedErr: ErrRebootSchedule,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.destination, func(t *testing.T) {
+			assert := assert.New(t)
+			e := Event{
+				Destination: tc.destination,
+			}
+
+			scheduled, delay, err := e.RebootSchedule()
+			assert.Equal(tc.expectedErr, err)
+			assert.True(tc.expectedTime.Equal(scheduled))
+			assert.Equal(tc.expectedDelay, delay)
+		})
+	}
+}
+
 func TestGetMetadataValue(t *testing.T) {
 	tests := []struct {
 		description string
@@ -335,3 +393,84 @@ func TestGetBirthDate(t *testing.T) {
 		})
 	}
 }
+
+func TestFingerprint(t *testing.T) {
+	event := Event{
+		MsgType:         4,
+		Source:          "dns:talaria",
+		Destination:     "event:device-status/mac:112233445566/online",
+		TransactionUUID: "1",
+		Metadata:        map[string]string{BootTimeKey: "60", "/trust": "1000"},
+		Payload:         `{"ts":"2021-03-02T18:00:01Z"}`,
+		Birthdate:       60,
+		PartnerIDs:      []string{"comcast"},
+		SessionID:       "a",
+	}
+
+	retransmitted := event
+	retransmitted.TransactionUUID = "2"
+
+	differentTrust := event
+	differentTrust.Metadata = map[string]string{BootTimeKey: "60", "/trust": "0"}
+
+	differentSession := event
+	differentSession.SessionID = "b"
+
+	tests := []struct {
+		description   string
+		other         Event
+		ignoredFields []string
+		expectedSame  bool
+	}{
+		{
+			description:  "same event",
+			other:        event,
+			expectedSame: true,
+		},
+		{
+			description:  "different transaction uuid",
+			other:        retransmitted,
+			expectedSame: true,
+		},
+		{
+			description: "different metadata",
+			other:       differentTrust,
+		},
+		{
+			description:   "ignored metadata key",
+			other:         differentTrust,
+			ignoredFields: []string{"metadata:trust"},
+			expectedSame:  true,
+		},
+		{
+			description:   "ignored metadata",
+			other:         differentTrust,
+			ignoredFields: []string{MetadataField},
+			expectedSame:  true,
+		},
+		{
+			description:   "different session ignoring other field",
+			other:         differentSession,
+			ignoredFields: []string{PayloadField},
+		},
+		{
+			description:   "ignored session",
+			other:         differentSession,
+			ignoredFields: []string{SessionIDField},
+			expectedSame:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			fingerprint := event.Fingerprint(tc.ignoredFields...)
+			assert.Len(fingerprint, 64)
+			if tc.expectedSame {
+				assert.Equal(fingerprint, tc.other.Fingerprint(tc.ignoredFields...))
+			} else {
+				assert.NotEqual(fingerprint, tc.other.Fingerprint(tc.ignoredFields...))
+			}
+		})
+	}
+}
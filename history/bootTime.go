@@ -0,0 +1,93 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// BootTimeClusters groups the boot-times found in a history so that boot-times within the tolerance
+// of each other are treated as one boot cycle. Boot-times are sorted and a new cluster is started
+// whenever the gap to the previous boot-time is larger than the tolerance. Every cluster is
+// represented by its earliest boot-time. The zero value treats every boot-time as its own cluster.
+type BootTimeClusters struct {
+	representatives map[int64]int64
+}
+
+// ClusterBootTimes clusters the boot-times of a list of events using the tolerance passed in.
+func ClusterBootTimes(events []interpreter.Event, tolerance time.Duration) BootTimeClusters {
+	return newOptions([]Option{WithBootTimeTolerance(tolerance)}).clusterBootTimes(events)
+}
+
+// clusterBootTimes clusters the boot-times of the events passed in, including any extra events.
+func (o options) clusterBootTimes(events []interpreter.Event, extra ...interpreter.Event) BootTimeClusters {
+	if o.bootTimeTolerance == 0 {
+		return BootTimeClusters{}
+	}
+
+	seen := make(map[int64]bool)
+	var bootTimes []int64
+	for _, eventList := range [][]interpreter.Event{events, extra} {
+		for _, event := range eventList {
+			if bootTime, _ := event.BootTime(); bootTime > 0 && !seen[bootTime] {
+				seen[bootTime] = true
+				bootTimes = append(bootTimes, bootTime)
+			}
+		}
+	}
+
+	sort.Slice(bootTimes, func(a, b int) bool {
+		return bootTimes[a] < bootTimes[b]
+	})
+
+	representatives := make(map[int64]int64, len(bootTimes))
+	var representative int64
+	for i, bootTime := range bootTimes {
+		if i == 0 || bootTime-bootTimes[i-1] > o.bootTimeTolerance {
+			representative = bootTime
+		}
+
+		representatives[bootTime] = representative
+	}
+
+	return BootTimeClusters{representatives: representatives}
+}
+
+// Normalize returns the boot-time representing the cluster that the boot-time belongs to.
+// Boot-times that were not clustered are returned as is.
+func (c BootTimeClusters) Normalize(bootTime int64) int64 {
+	if representative, found := c.representatives[bootTime]; found {
+		return representative
+	}
+
+	return bootTime
+}
+
+// BootTime returns the normalized boot-time of an event, along with any error from parsing the boot-time.
+func (c BootTimeClusters) BootTime(event interpreter.Event) (int64, error) {
+	bootTime, err := event.BootTime()
+	return c.Normalize(bootTime), err
+}
+
+// Same returns whether two boot-times belong to the same cluster.
+func (c BootTimeClusters) Same(a int64, b int64) bool {
+	return c.Normalize(a) == c.Normalize(b)
+}
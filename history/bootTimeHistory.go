@@ -0,0 +1,177 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrBootTimeRegression   = errors.New("boot-time went backwards")
+	ErrClockResetBootTime   = errors.New("boot-time is a clock-reset default")
+	ErrSilentBootTimeChange = errors.New("boot-time changed without the device reconnecting")
+
+	// DefaultClockResetBefore is the default time before which boot-times are treated as clock-reset defaults,
+	// which catches devices that report boot-times near the unix epoch or at the start of the year 2000.
+	DefaultClockResetBefore = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// BootTimeValidators returns the validators that check how boot-times evolve across a device's history:
+// BootTimeRegressionValidator, ClockResetValidator and BootTimeChangeValidator.
+func BootTimeValidators(opts ...Option) CycleValidators {
+	return CycleValidators{
+		BootTimeRegressionValidator(opts...),
+		ClockResetValidator(opts...),
+		BootTimeChangeValidator(opts...),
+	}
+}
+
+// BootTimeRegressionValidator returns a CycleValidatorFunc that walks the events from oldest to newest by birthdate
+// and validates that boot-times never go backwards, meaning that no event has an older boot-time than an event born
+// before it. Boot-times within the boot-time tolerance of each other are considered the same, and clock-reset
+// boot-times are left to ClockResetValidator. It is meant to be run on a device's whole history.
+func BootTimeRegressionValidator(opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
+	return func(events []interpreter.Event) (bool, error) {
+		clusters := o.clusterBootTimes(events)
+		var details []string
+		var regressed []interpreter.Event
+		var latest interpreter.Event
+		var latestBootTime int64
+		for _, event := range o.bootTimeEvents(events) {
+			bootTime, _ := clusters.BootTime(event)
+			if latestBootTime > 0 && bootTime < latestBootTime {
+				details = append(details, fmt.Sprintf("%s (%d) after %s (%d)", event.TransactionUUID, bootTime, latest.TransactionUUID, latestBootTime))
+				regressed = append(regressed, event)
+				continue
+			}
+
+			latest = event
+			latestBootTime = bootTime
+		}
+
+		if len(details) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrBootTimeRegression,
+			ErrorDetailKey:    "regressed events",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.BootTimeRegression,
+			OffendingEvents:   regressed,
+		}
+	}
+}
+
+// ClockResetValidator returns a CycleValidatorFunc that validates that no event has a boot-time from before
+// the time set by WithClockResetBefore, which defaults to DefaultClockResetBefore. Events without a boot-time
+// are skipped.
+func ClockResetValidator(opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
+	return func(events []interpreter.Event) (bool, error) {
+		var details []string
+		var reset []interpreter.Event
+		for _, event := range events {
+			if bootTime, err := event.BootTime(); err == nil && bootTime > 0 && o.isClockReset(bootTime) {
+				details = append(details, fmt.Sprintf("%s (%s)", event.TransactionUUID, time.Unix(bootTime, 0).UTC().Format(time.RFC3339)))
+				reset = append(reset, event)
+			}
+		}
+
+		if len(details) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrClockResetBootTime,
+			ErrorDetailKey:    "clock-reset events",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.ClockResetBootTime,
+			OffendingEvents:   reset,
+		}
+	}
+}
+
+// BootTimeChangeValidator returns a CycleValidatorFunc that walks the events from oldest to newest by birthdate and
+// validates that the device reconnected whenever the boot-time changed, meaning that either the last event with the
+// previous boot-time is an offline event or the first event with the new boot-time is an online event, based on the
+// session end and start roles in the event type registry. Boot-times within the boot-time tolerance of each other
+// are considered the same, and clock-reset boot-times are left to ClockResetValidator.
+func BootTimeChangeValidator(opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
+	return func(events []interpreter.Event) (bool, error) {
+		clusters := o.clusterBootTimes(events)
+		var details []string
+		var changed []interpreter.Event
+		var previous *interpreter.Event
+		var previousBootTime int64
+		for _, event := range o.bootTimeEvents(events) {
+			event := event
+			bootTime, _ := clusters.BootTime(event)
+			if previous != nil && bootTime != previousBootTime &&
+				!o.hasRole(*previous, interpreter.SessionEndRole) && !o.hasRole(event, interpreter.SessionStartRole) {
+				details = append(details, fmt.Sprintf("%s (%d) -> %s (%d)", previous.TransactionUUID, previousBootTime, event.TransactionUUID, bootTime))
+				changed = append(changed, *previous, event)
+			}
+
+			previous = &event
+			previousBootTime = bootTime
+		}
+
+		if len(details) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrSilentBootTimeChange,
+			ErrorDetailKey:    "boot-time changes",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.SilentBootTimeChange,
+			OffendingEvents:   uniqueEvents(changed),
+		}
+	}
+}
+
+// isClockReset returns whether the boot-time is a clock-reset default.
+func (o options) isClockReset(bootTime int64) bool {
+	return bootTime < o.clockResetBefore
+}
+
+// bootTimeEvents returns the events with valid boot-times that are not clock-reset defaults,
+// sorted from oldest to newest by birthdate.
+func (o options) bootTimeEvents(events []interpreter.Event) []interpreter.Event {
+	var filtered []interpreter.Event
+	for _, event := range events {
+		if bootTime, err := event.BootTime(); err == nil && bootTime > 0 && !o.isClockReset(bootTime) {
+			filtered = append(filtered, event)
+		}
+	}
+
+	sort.SliceStable(filtered, func(a, b int) bool {
+		return filtered[a].Birthdate < filtered[b].Birthdate
+	})
+
+	return filtered
+}
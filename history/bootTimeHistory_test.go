@@ -0,0 +1,197 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestBootTimeRegressionValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	tests := []struct {
+		description     string
+		events          []interpreter.Event
+		opts            []Option
+		expectedDetails []string
+	}{
+		{
+			description: "valid",
+			events: []interpreter.Event{
+				testCycleEvent("3", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+				testCycleEvent("2", "offline", bootTime1, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+			},
+		},
+		{
+			description: "empty",
+		},
+		{
+			description: "regression",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+				testCycleEvent("3", "operational", bootTime1, now.Add(-40*time.Minute), "b"),
+				testCycleEvent("4", "operational", bootTime2, now.Add(-30*time.Minute), "b"),
+			},
+			expectedDetails: []string{"3 (1614700801) after 2 (1614704401)"},
+		},
+		{
+			description: "within tolerance",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1+1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "operational", bootTime1, now.Add(-100*time.Minute), "a"),
+			},
+			opts: []Option{WithBootTimeTolerance(time.Second)},
+		},
+		{
+			description: "clock-reset boot-time skipped",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", 10, now.Add(-100*time.Minute), "b"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			testBootTimeHistoryValidator(t, BootTimeRegressionValidator(tc.opts...), tc.events, validation.BootTimeRegression, ErrBootTimeRegression, tc.expectedDetails)
+		})
+	}
+}
+
+func TestClockResetValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	y2k := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	tests := []struct {
+		description     string
+		events          []interpreter.Event
+		opts            []Option
+		expectedDetails []string
+	}{
+		{
+			description: "valid",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", 0, now.Add(-100*time.Minute), "b"),
+			},
+		},
+		{
+			description: "clock-reset boot-times",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", 60, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-100*time.Minute), "b"),
+				testCycleEvent("3", "online", y2k, now.Add(-90*time.Minute), "c"),
+			},
+			expectedDetails: []string{"1 (1970-01-01T00:01:00Z)", "3 (2000-01-01T00:00:00Z)"},
+		},
+		{
+			description: "custom cutoff",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+			},
+			opts:            []Option{WithClockResetBefore(now)},
+			expectedDetails: []string{"1 (2021-03-02T16:00:01Z)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			testBootTimeHistoryValidator(t, ClockResetValidator(tc.opts...), tc.events, validation.ClockResetBootTime, ErrClockResetBootTime, tc.expectedDetails)
+		})
+	}
+}
+
+func TestBootTimeChangeValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	bootTime3 := now.Add(-30 * time.Minute).Unix()
+	tests := []struct {
+		description     string
+		events          []interpreter.Event
+		expectedDetails []string
+	}{
+		{
+			description: "offline and online",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "offline", bootTime1, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("3", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+			},
+		},
+		{
+			description: "missing offline",
+			events: []interpreter.Event{
+				testCycleEvent("1", "operational", bootTime1, now.Add(-100*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+			},
+		},
+		{
+			description: "missing online",
+			events: []interpreter.Event{
+				testCycleEvent("1", "offline", bootTime1, now.Add(-100*time.Minute), "a"),
+				testCycleEvent("2", "operational", bootTime2, now.Add(-50*time.Minute), "b"),
+			},
+		},
+		{
+			description: "no reconnect",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "operational", bootTime1, now.Add(-100*time.Minute), "a"),
+				testCycleEvent("3", "fully-manageable", bootTime2, now.Add(-50*time.Minute), "a"),
+				testCycleEvent("4", "offline", bootTime2, now.Add(-40*time.Minute), "a"),
+				testCycleEvent("5", "online", bootTime3, now.Add(-20*time.Minute), "b"),
+			},
+			expectedDetails: []string{"2 (1614700801) -> 3 (1614704401)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			testBootTimeHistoryValidator(t, BootTimeChangeValidator(), tc.events, validation.SilentBootTimeChange, ErrSilentBootTimeChange, tc.expectedDetails)
+		})
+	}
+}
+
+func TestBootTimeValidators(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", now.Add(-time.Hour).Unix(), now.Add(-50*time.Minute), "a"),
+		testCycleEvent("2", "operational", now.Add(-2*time.Hour).Unix(), now.Add(-40*time.Minute), "a"),
+		testCycleEvent("3", "operational", 60, now.Add(-30*time.Minute), "a"),
+	}
+
+	valid, err := BootTimeValidators().Valid(events)
+	assert.False(t, valid)
+	var errs validation.Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, []validation.Tag{validation.BootTimeRegression, validation.ClockResetBootTime, validation.SilentBootTimeChange}, errs.Tags())
+}
+
+func testBootTimeHistoryValidator(t *testing.T, validator CycleValidator, events []interpreter.Event, expectedTag validation.Tag, expectedErr error, expectedDetails []string) {
+	assert := assert.New(t)
+	valid, err := validator.Valid(events)
+	if len(expectedDetails) == 0 {
+		assert.True(valid)
+		assert.Nil(err)
+		return
+	}
+
+	assert.False(valid)
+	assert.True(errors.Is(err, expectedErr))
+	var cvErr CycleValidationErr
+	assert.True(errors.As(err, &cvErr))
+	assert.Equal(expectedTag, cvErr.Tag())
+	assert.Equal(expectedDetails, cvErr.Fields())
+}
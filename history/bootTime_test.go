@@ -0,0 +1,137 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestWithBootTimeTolerance(t *testing.T) {
+	tests := []struct {
+		description string
+		opts        []Option
+		expected    int64
+	}{
+		{
+			description: "no options",
+			expected:    0,
+		},
+		{
+			description: "nil option",
+			opts:        []Option{nil},
+			expected:    0,
+		},
+		{
+			description: "seconds",
+			opts:        []Option{WithBootTimeTolerance(2 * time.Second)},
+			expected:    2,
+		},
+		{
+			description: "negative tolerance",
+			opts:        []Option{WithBootTimeTolerance(-3 * time.Second)},
+			expected:    3,
+		},
+		{
+			description: "less than a second",
+			opts:        []Option{WithBootTimeTolerance(500 * time.Millisecond)},
+			expected:    0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, newOptions(tc.opts).bootTimeTolerance)
+		})
+	}
+}
+
+func TestClusterBootTimes(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-1 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime, now, "a"),
+		testCycleEvent("2", "operational", bootTime+1, now, "a"),
+		testCycleEvent("3", "fully-manageable", bootTime+3, now, "a"),
+		testCycleEvent("4", "online", bootTime+10, now, "b"),
+		testCycleEvent("5", "online", -1, now, "c"),
+		interpreter.Event{TransactionUUID: "no-boot-time"},
+	}
+
+	tests := []struct {
+		description string
+		tolerance   time.Duration
+		expected    map[int64]int64
+	}{
+		{
+			description: "no tolerance",
+			expected: map[int64]int64{
+				bootTime:      bootTime,
+				bootTime + 1:  bootTime + 1,
+				bootTime + 3:  bootTime + 3,
+				bootTime + 10: bootTime + 10,
+				-1:            -1,
+			},
+		},
+		{
+			description: "one second",
+			tolerance:   time.Second,
+			expected: map[int64]int64{
+				bootTime:      bootTime,
+				bootTime + 1:  bootTime,
+				bootTime + 3:  bootTime + 3,
+				bootTime + 10: bootTime + 10,
+				-1:            -1,
+			},
+		},
+		{
+			description: "chained boot-times",
+			tolerance:   2 * time.Second,
+			expected: map[int64]int64{
+				bootTime:      bootTime,
+				bootTime + 1:  bootTime,
+				bootTime + 3:  bootTime,
+				bootTime + 10: bootTime + 10,
+				-1:            -1,
+			},
+		},
+		{
+			description: "large tolerance",
+			tolerance:   time.Minute,
+			expected: map[int64]int64{
+				bootTime:      bootTime,
+				bootTime + 1:  bootTime,
+				bootTime + 3:  bootTime,
+				bootTime + 10: bootTime,
+				-1:            -1,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			clusters := ClusterBootTimes(events, tc.tolerance)
+			for original, normalized := range tc.expected {
+				assert.Equal(normalized, clusters.Normalize(original))
+			}
+
+			normalized, err := clusters.BootTime(events[2])
+			assert.Nil(err)
+			assert.Equal(tc.expected[bootTime+3], normalized)
+			assert.Equal(tc.expected[bootTime] == tc.expected[bootTime+1], clusters.Same(bootTime, bootTime+1))
+		})
+	}
+}
+
+func TestBootTimeClustersZeroValue(t *testing.T) {
+	assert := assert.New(t)
+	var clusters BootTimeClusters
+	assert.Equal(int64(100), clusters.Normalize(100))
+	assert.True(clusters.Same(100, 100))
+	assert.False(clusters.Same(100, 101))
+	_, err := clusters.BootTime(interpreter.Event{})
+	assert.NotNil(err)
+}
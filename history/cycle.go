@@ -0,0 +1,146 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// Cycle is a boot cycle: all of the events in a history that share the same boot-time.
+type Cycle struct {
+	// BootTime is the boot-time representing the cycle. When a boot-time tolerance is used,
+	// it is the earliest boot-time of the events in the cycle.
+	BootTime int64
+
+	// events are sorted from oldest to newest by birthdate.
+	events []interpreter.Event
+}
+
+// SplitIntoCycles takes in a list of events and splits it into boot cycles in a single pass over the events.
+// Events with boot-times within the boot-time tolerance of each other are placed in the same cycle.
+// Events without a valid boot-time are not included in any cycle. The returned cycles are sorted
+// from newest to oldest by boot-time.
+func SplitIntoCycles(events []interpreter.Event, opts ...Option) []Cycle {
+	clusters := newOptions(opts).clusterBootTimes(events)
+	cyclesMap := make(map[int64][]interpreter.Event)
+	for _, event := range events {
+		bootTime, _ := clusters.BootTime(event)
+		if bootTime <= 0 {
+			continue
+		}
+
+		cyclesMap[bootTime] = append(cyclesMap[bootTime], event)
+	}
+
+	cycles := make([]Cycle, 0, len(cyclesMap))
+	for bootTime, cycleEvents := range cyclesMap {
+		cycles = append(cycles, newCycle(bootTime, cycleEvents))
+	}
+
+	sort.Slice(cycles, func(a, b int) bool {
+		return cycles[a].BootTime > cycles[b].BootTime
+	})
+
+	return cycles
+}
+
+func newCycle(bootTime int64, events []interpreter.Event) Cycle {
+	sort.SliceStable(events, func(a, b int) bool {
+		return events[a].Birthdate < events[b].Birthdate
+	})
+
+	return Cycle{BootTime: bootTime, events: events}
+}
+
+// Len returns the number of events in the cycle.
+func (c Cycle) Len() int {
+	return len(c.events)
+}
+
+// OldestToNewest returns the events in the cycle sorted from oldest to newest by birthdate.
+func (c Cycle) OldestToNewest() []interpreter.Event {
+	events := make([]interpreter.Event, len(c.events))
+	copy(events, c.events)
+	return events
+}
+
+// NewestToOldest returns the events in the cycle sorted from newest to oldest by birthdate.
+func (c Cycle) NewestToOldest() []interpreter.Event {
+	events := make([]interpreter.Event, len(c.events))
+	for i, event := range c.events {
+		events[len(c.events)-1-i] = event
+	}
+
+	return events
+}
+
+// FirstBirthdate returns the birthdate of the oldest event in the cycle.
+func (c Cycle) FirstBirthdate() time.Time {
+	if len(c.events) == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, c.events[0].Birthdate)
+}
+
+// LastBirthdate returns the birthdate of the newest event in the cycle.
+func (c Cycle) LastBirthdate() time.Time {
+	if len(c.events) == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, c.events[len(c.events)-1].Birthdate)
+}
+
+// Duration returns the time between the oldest and the newest events in the cycle.
+func (c Cycle) Duration() time.Duration {
+	return c.LastBirthdate().Sub(c.FirstBirthdate())
+}
+
+// Sessions returns the unique session ids found in the cycle, in the order
+// that they first appear from oldest to newest.
+func (c Cycle) Sessions() []string {
+	var sessions []string
+	seen := make(map[string]bool)
+	for _, event := range c.events {
+		if len(event.SessionID) == 0 || seen[event.SessionID] {
+			continue
+		}
+
+		seen[event.SessionID] = true
+		sessions = append(sessions, event.SessionID)
+	}
+
+	return sessions
+}
+
+// EventTypeCounts returns the number of events of each event type in the cycle.
+// Events without a parsable event type are not counted.
+func (c Cycle) EventTypeCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, event := range c.events {
+		if eventType, err := event.EventType(); err == nil {
+			counts[eventType]++
+		}
+	}
+
+	return counts
+}
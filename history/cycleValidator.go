@@ -82,14 +82,16 @@ func (c CycleValidators) Valid(events []interpreter.Event) (bool, error) {
 // MetadataValidator takes in a slice of metadata keys and returns a CycleValidatorFunc that
 // validates that events in the slice have the same values for the keys passed in. If
 // checkWithinCycle is true, it will only check that events with the same boot-time have the same
-// values.
-func MetadataValidator(fields []string, checkWithinCycle bool) CycleValidatorFunc {
+// values, where boot-times within the boot-time tolerance of each other are considered the same.
+func MetadataValidator(fields []string, checkWithinCycle bool, opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
 	return func(events []interpreter.Event) (bool, error) {
 		var incorrectFields []string
+		var offendingEvents []interpreter.Event
 		if checkWithinCycle {
-			incorrectFields = validateMetadataWithinCycle(fields, events)
+			incorrectFields, offendingEvents = validateMetadataWithinCycle(fields, events, o.clusterBootTimes(events))
 		} else {
-			incorrectFields = validateMetadata(fields, events)
+			incorrectFields, offendingEvents = validateMetadata(fields, events)
 		}
 
 		if len(incorrectFields) == 0 {
@@ -108,6 +110,7 @@ func MetadataValidator(fields []string, checkWithinCycle bool) CycleValidatorFun
 			ErrorDetailKey:    "inconsistent metadata keys",
 			ErrorDetailValues: incorrectFields,
 			ErrorTag:          validation.InconsistentMetadata,
+			OffendingEvents:   offendingEvents,
 		}
 	}
 }
@@ -136,21 +139,33 @@ func TransactionUUIDValidator() CycleValidatorFunc {
 			return true, nil
 		}
 
+		var repeatedEvents []interpreter.Event
+		for _, event := range events {
+			if ids[event.TransactionUUID] {
+				repeatedEvents = append(repeatedEvents, event)
+			}
+		}
+
 		return false, CycleValidationErr{
 			OriginalErr:       ErrRepeatID,
 			ErrorDetailKey:    "repeated uuids",
 			ErrorDetailValues: repeatIDSlice,
 			ErrorTag:          validation.RepeatedTransactionUUID,
+			OffendingEvents:   repeatedEvents,
 		}
 	}
 }
 
 // SessionOnlineValidator returns a CycleValidatorFunc that validates that all sessions in the slice
-// (determined by sessionIDs) have an online event. It takes in excludeFunc, which is a function that
-// takes in a session ID and returns true if that session is still valid even if it does not have an online event.
-func SessionOnlineValidator(excludeFunc func(events []interpreter.Event, id string) bool) CycleValidatorFunc {
+// (determined by sessionIDs) have an online event, which is any event type with the session start role
+// in the event type registry. It takes in excludeFunc, which is a function that takes in a session ID and
+// returns true if that session is still valid even if it does not have an online event.
+func SessionOnlineValidator(excludeFunc func(events []interpreter.Event, id string) bool, opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
 	return func(events []interpreter.Event) (bool, error) {
-		sessionsWithOnline := parseSessions(events, interpreter.OnlineEventType)
+		sessionsWithOnline := parseSessions(events, func(event interpreter.Event) bool {
+			return o.hasRole(event, interpreter.SessionStartRole)
+		})
 		invalidIds := findSessionsWithoutEvent(sessionsWithOnline, events, excludeFunc)
 		if len(invalidIds) == 0 {
 			return true, nil
@@ -161,21 +176,26 @@ func SessionOnlineValidator(excludeFunc func(events []interpreter.Event, id stri
 			ErrorDetailKey:    "session ids",
 			ErrorDetailValues: invalidIds,
 			ErrorTag:          validation.MissingOnlineEvent,
+			OffendingEvents:   sessionEvents(events, invalidIds),
 		}
 
 	}
 }
 
 // SessionOfflineValidator returns a CycleValidatorFunc that validates that all sessions in the slice
-// (except for the most recent session) have an offline event. It takes in excludeFunc, which is a function that
-// takes in a session ID and returns true if that session is still valid even if it does not have an offline event.
-func SessionOfflineValidator(excludeFunc func(events []interpreter.Event, id string) bool) CycleValidatorFunc {
+// (except for the most recent session) have an offline event, which is any event type with the session end
+// role in the event type registry. It takes in excludeFunc, which is a function that takes in a session ID and
+// returns true if that session is still valid even if it does not have an offline event.
+func SessionOfflineValidator(excludeFunc func(events []interpreter.Event, id string) bool, opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
 	return func(events []interpreter.Event) (bool, error) {
 		if len(events) == 0 {
 			return true, nil
 		}
 
-		sessionsWithOffline := parseSessions(events, interpreter.OfflineEventType)
+		sessionsWithOffline := parseSessions(events, func(event interpreter.Event) bool {
+			return o.hasRole(event, interpreter.SessionEndRole)
+		})
 		invalidIds := findSessionsWithoutEvent(sessionsWithOffline, events, excludeFunc)
 		if len(invalidIds) == 0 {
 			return true, nil
@@ -186,13 +206,15 @@ func SessionOfflineValidator(excludeFunc func(events []interpreter.Event, id str
 			ErrorDetailKey:    "session ids",
 			ErrorDetailValues: invalidIds,
 			ErrorTag:          validation.MissingOfflineEvent,
+			OffendingEvents:   sessionEvents(events, invalidIds),
 		}
 
 	}
 }
 
 // EventOrderValidator returns a CycleValidatorFunc that validates that there exists, within the history of events,
-// particular events in the proper order.
+// particular events in the proper order. Once the first event type is found, the events that follow it must match
+// the rest of the order exactly. Use SequenceValidator for optional events, repetition, alternatives and time bounds.
 func EventOrderValidator(order []string) CycleValidatorFunc {
 	return func(events []interpreter.Event) (bool, error) {
 		if len(order) == 0 {
@@ -202,6 +224,8 @@ func EventOrderValidator(order []string) CycleValidatorFunc {
 		currentIndex := 0
 		validOrder := true
 		var actualOrder []string
+		// orderEvents are the events in actualOrder, and outOfOrder are the events that did not match the order
+		var orderEvents, outOfOrder []interpreter.Event
 		for _, event := range events {
 			if currentIndex >= len(order) {
 				break
@@ -210,25 +234,34 @@ func EventOrderValidator(order []string) CycleValidatorFunc {
 			eventType, _ := event.EventType()
 			if currentIndex > 0 {
 				actualOrder = append(actualOrder, eventType)
+				orderEvents = append(orderEvents, event)
 				if eventType != order[currentIndex] {
 					validOrder = false
+					outOfOrder = append(outOfOrder, event)
 				} else {
 					currentIndex++
 				}
 			} else if currentIndex == 0 {
 				if eventType == order[currentIndex] {
 					actualOrder = append(actualOrder, eventType)
+					orderEvents = append(orderEvents, event)
 					currentIndex++
 				}
 			}
 		}
 
 		if !validOrder || currentIndex != len(order) {
+			// when the order is incomplete rather than broken, every event found is part of the problem
+			if len(outOfOrder) == 0 {
+				outOfOrder = orderEvents
+			}
+
 			return false, CycleValidationErr{
 				OriginalErr:       ErrInvalidEventOrder,
 				ErrorDetailKey:    "event_order",
 				ErrorDetailValues: actualOrder,
 				ErrorTag:          validation.InvalidEventOrder,
+				OffendingEvents:   outOfOrder,
 			}
 		}
 
@@ -238,14 +271,20 @@ func EventOrderValidator(order []string) CycleValidatorFunc {
 
 // TrueRebootValidator returns a CycleValidatorFunc that validates that the latest online event is the result of a
 // true reboot, meaning that it has a boot-time that is different from the event that precedes it.
-// If an online event is not found, false and an error is returned.
-func TrueRebootValidator() CycleValidatorFunc {
+// Boot-times within the boot-time tolerance of each other are considered the same, and online events are
+// any event type with the session start role in the event type registry.
+// If an online event is not found, false and an error is returned. The events must span the boot cycle before the
+// latest online event, such as a device's whole history or the events returned by RebootToCurrentParser, since within
+// a single boot cycle every online event after the oldest event looks like a false reboot.
+func TrueRebootValidator(opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
 	return func(events []interpreter.Event) (bool, error) {
+		clusters := o.clusterBootTimes(events)
 		eventsCopy := make([]interpreter.Event, len(events))
 		copy(eventsCopy, events)
 		sort.Slice(eventsCopy, func(a, b int) bool {
-			boottimeA, _ := eventsCopy[a].BootTime()
-			boottimeB, _ := eventsCopy[b].BootTime()
+			boottimeA, _ := clusters.BootTime(eventsCopy[a])
+			boottimeB, _ := clusters.BootTime(eventsCopy[b])
 			if boottimeA != boottimeB {
 				return boottimeA > boottimeB
 			}
@@ -254,16 +293,16 @@ func TrueRebootValidator() CycleValidatorFunc {
 		})
 
 		for i, event := range eventsCopy {
-			eventType, _ := event.EventType()
-			if eventType == interpreter.OnlineEventType {
+			if o.hasRole(event, interpreter.SessionStartRole) {
 				if i < len(eventsCopy)-1 {
 					nextEvent := eventsCopy[i+1]
-					currentBootTime, err := event.BootTime()
-					nextEventBootTime, e := nextEvent.BootTime()
+					currentBootTime, err := clusters.BootTime(event)
+					nextEventBootTime, e := clusters.BootTime(nextEvent)
 					if err != nil || e != nil || currentBootTime == nextEventBootTime {
 						return false, CycleValidationErr{
-							OriginalErr: ErrFalseReboot,
-							ErrorTag:    validation.FalseReboot,
+							OriginalErr:     ErrFalseReboot,
+							ErrorTag:        validation.FalseReboot,
+							OffendingEvents: []interpreter.Event{event, nextEvent},
 						}
 					}
 				}
@@ -281,11 +320,11 @@ func TrueRebootValidator() CycleValidatorFunc {
 
 // go through list of events and save all session ids seen in the list as well as whether that session
 // has the event being looked for.
-func parseSessions(events []interpreter.Event, searchedEventType string) map[string]bool {
+func parseSessions(events []interpreter.Event, searched func(interpreter.Event) bool) map[string]bool {
 	eventsMap := make(map[string]bool)
 	for _, event := range events {
 		sessionID := event.SessionID
-		eventType, err := event.EventType()
+		_, err := event.EventType()
 		if len(sessionID) == 0 || err != nil {
 			continue
 		}
@@ -294,7 +333,7 @@ func parseSessions(events []interpreter.Event, searchedEventType string) map[str
 			eventsMap[sessionID] = false
 		}
 
-		if eventType == searchedEventType {
+		if searched(event) {
 			eventsMap[sessionID] = true
 		}
 
@@ -302,6 +341,23 @@ func parseSessions(events []interpreter.Event, searchedEventType string) map[str
 	return eventsMap
 }
 
+// sessionEvents returns the events that belong to any of the sessions.
+func sessionEvents(events []interpreter.Event, sessionIDs []string) []interpreter.Event {
+	ids := make(map[string]bool, len(sessionIDs))
+	for _, id := range sessionIDs {
+		ids[id] = true
+	}
+
+	var found []interpreter.Event
+	for _, event := range events {
+		if ids[event.SessionID] {
+			found = append(found, event)
+		}
+	}
+
+	return found
+}
+
 func findSessionsWithoutEvent(eventsMap map[string]bool, eventsList []interpreter.Event, exclude func(events []interpreter.Event, id string) bool) []string {
 	if exclude == nil {
 		exclude = func(_ []interpreter.Event, _ string) bool {
@@ -328,9 +384,11 @@ func determineMetadataValues(fields []string, event interpreter.Event) map[strin
 	return values
 }
 
-func validateMetadata(keys []string, events []interpreter.Event) []string {
+// validate that metadata is the same across all events, returning the inconsistent keys along with
+// the events whose values differ from the most common values.
+func validateMetadata(keys []string, events []interpreter.Event) ([]string, []interpreter.Event) {
 	if len(events) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// save what the metadata values are supposed to be for all following events
@@ -342,7 +400,7 @@ func validateMetadata(keys []string, events []interpreter.Event) []string {
 	}
 
 	if len(incorrectFieldsMap) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	fields := make([]string, 0, len(incorrectFieldsMap))
@@ -350,31 +408,38 @@ func validateMetadata(keys []string, events []interpreter.Event) []string {
 		fields = append(fields, key)
 	}
 
-	return fields
+	return fields, minorityEvents(fields, events)
 
 }
 
-// validate that metdata is the same within events with the same boot-time
-func validateMetadataWithinCycle(keys []string, events []interpreter.Event) []string {
+// validate that metdata is the same within events with the same boot-time, after
+// the boot-times have been normalized by the clusters passed in. The inconsistent keys are returned
+// along with the events whose values differ from the most common values among events with the same boot-time.
+func validateMetadataWithinCycle(keys []string, events []interpreter.Event, clusters BootTimeClusters) ([]string, []interpreter.Event) {
 	if len(events) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// map saving the metadata values that all events with a certain boot-time must have
 	metadataVals := make(map[int64]map[string]string)
 	incorrectFieldsMap := make(map[string]bool)
+	// the events of each boot-time, in the order that the boot-times were found
+	var bootTimes []int64
+	cycles := make(map[int64][]interpreter.Event)
 	for _, event := range events {
-		boottime, err := event.BootTime()
+		boottime, err := clusters.BootTime(event)
 		if err != nil || boottime <= 0 {
 			continue
 		}
 
 		expectedVals, found := metadataVals[boottime]
+		cycles[boottime] = append(cycles[boottime], event)
 		// if metadata values for that boot-time does not exist, this is the first time we've encountered
 		// an event with this boot-time, so find the values of the metadata keys and save them in the map
 		// to reference later.
 		if !found {
 			metadataVals[boottime] = determineMetadataValues(keys, event)
+			bootTimes = append(bootTimes, boottime)
 			continue
 		}
 
@@ -383,7 +448,7 @@ func validateMetadataWithinCycle(keys []string, events []interpreter.Event) []st
 	}
 
 	if len(incorrectFieldsMap) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	fields := make([]string, 0, len(incorrectFieldsMap))
@@ -391,8 +456,55 @@ func validateMetadataWithinCycle(keys []string, events []interpreter.Event) []st
 		fields = append(fields, key)
 	}
 
-	return fields
+	var incorrectEvents []interpreter.Event
+	for _, boottime := range bootTimes {
+		incorrectEvents = append(incorrectEvents, minorityEvents(fields, cycles[boottime])...)
+	}
+
+	return fields, incorrectEvents
+
+}
+
+// minorityEvents returns the events whose value of any of the keys differs from the value most of the events have,
+// in the order they were passed in. If no single value is the most common for a key, there is no telling which
+// events are wrong, so every event is returned.
+func minorityEvents(keys []string, events []interpreter.Event) []interpreter.Event {
+	minority := make([]bool, len(events))
+	for _, key := range keys {
+		counts := make(map[string]int)
+		values := make([]string, len(events))
+		for i, event := range events {
+			values[i], _ = event.GetMetadataValue(key)
+			counts[values[i]]++
+		}
+
+		var majority string
+		var most int
+		tied := false
+		for value, count := range counts {
+			switch {
+			case count > most:
+				majority, most, tied = value, count, false
+			case count == most:
+				tied = true
+			}
+		}
+
+		for i, value := range values {
+			if tied || value != majority {
+				minority[i] = true
+			}
+		}
+	}
+
+	var found []interpreter.Event
+	for i, event := range events {
+		if minority[i] {
+			found = append(found, event)
+		}
+	}
 
+	return found
 }
 
 // compare an event's metadata values with the values it is supposed to have
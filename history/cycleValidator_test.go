@@ -1031,7 +1031,10 @@ func TestParseSessions(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
 			assert := assert.New(t)
-			sessionMap := parseSessions(tc.events, "online")
+			sessionMap := parseSessions(tc.events, func(event interpreter.Event) bool {
+				eventType, _ := event.EventType()
+				return eventType == "online"
+			})
 			assert.Equal(tc.expectedMap, sessionMap)
 		})
 	}
@@ -1228,7 +1231,7 @@ func TestValidateMetadata(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			invalidKeys := validateMetadata(keys, tc.events)
+			invalidKeys, _ := validateMetadata(keys, tc.events)
 			assert.ElementsMatch(t, tc.expectedInvalid, invalidKeys)
 		})
 	}
@@ -1338,8 +1341,64 @@ func TestValidateMetadataWithinCycle(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			invalidKeys := validateMetadataWithinCycle(fields, tc.events)
+			invalidKeys, _ := validateMetadataWithinCycle(fields, tc.events, BootTimeClusters{})
 			assert.ElementsMatch(t, tc.expectedInvalid, invalidKeys)
 		})
 	}
 }
+
+func TestCycleValidatorsWithTolerance(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-1 * time.Hour).Unix()
+	online := testCycleEvent("1", "online", bootTime+1, now.Add(-50*time.Minute), "a")
+	online.Metadata["fw-name"] = "fw1"
+	operational := testCycleEvent("2", "operational", bootTime, now.Add(-55*time.Minute), "a")
+	operational.Metadata["fw-name"] = "fw2"
+	events := []interpreter.Event{online, operational}
+
+	assert := assert.New(t)
+	valid, err := TrueRebootValidator().Valid(events)
+	assert.True(valid)
+	assert.Nil(err)
+	valid, err = TrueRebootValidator(WithBootTimeTolerance(time.Second)).Valid(events)
+	assert.False(valid)
+	assert.True(errors.Is(err, ErrFalseReboot))
+
+	valid, err = MetadataValidator([]string{"fw-name"}, true).Valid(events)
+	assert.True(valid)
+	assert.Nil(err)
+	valid, err = MetadataValidator([]string{"fw-name"}, true, WithBootTimeTolerance(time.Second)).Valid(events)
+	assert.False(valid)
+	assert.True(errors.Is(err, ErrInconsistentMetadata))
+}
+
+func TestCycleValidatorsWithEventTypeRegistry(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-1 * time.Hour).Unix()
+	registry := interpreter.NewEventTypeRegistry(
+		interpreter.EventTypeInfo{Name: "connected", Roles: interpreter.SessionStartRole, Order: 1},
+		interpreter.EventTypeInfo{Name: "disconnected", Roles: interpreter.SessionEndRole, Order: 2},
+	)
+	events := []interpreter.Event{
+		testCycleEvent("1", "connected", bootTime, now.Add(-50*time.Minute), "a"),
+		testCycleEvent("2", "disconnected", bootTime, now.Add(-40*time.Minute), "a"),
+	}
+	excludeFunc := func(_ []interpreter.Event, _ string) bool { return false }
+
+	assert := assert.New(t)
+	valid, err := SessionOnlineValidator(excludeFunc).Valid(events)
+	assert.False(valid)
+	assert.True(errors.Is(err, ErrMissingOnlineEvent))
+	valid, err = SessionOnlineValidator(excludeFunc, WithEventTypeRegistry(registry)).Valid(events)
+	assert.True(valid)
+	assert.Nil(err)
+
+	valid, err = SessionOfflineValidator(excludeFunc).Valid(events)
+	assert.False(valid)
+	assert.True(errors.Is(err, ErrMissingOfflineEvent))
+	valid, err = SessionOfflineValidator(excludeFunc, WithEventTypeRegistry(registry)).Valid(events)
+	assert.True(valid)
+	assert.Nil(err)
+}
@@ -0,0 +1,112 @@
+package history
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestSplitIntoCycles(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+		testCycleEvent("2", "offline", bootTime1, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("3", "operational", bootTime2, now.Add(-40*time.Minute), "b"),
+		testCycleEvent("4", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("5", "fully-manageable", bootTime2, now.Add(-30*time.Minute), "c"),
+		interpreter.Event{TransactionUUID: "no-boot-time", Destination: "event:device-status/mac:112233445566/online"},
+		testCycleEvent("6", "online", -1, now, "d"),
+	}
+
+	cycles := SplitIntoCycles(events)
+	assert := assert.New(t)
+	assert.Len(cycles, 2)
+	assert.Equal(bootTime2, cycles[0].BootTime)
+	assert.Equal(bootTime1, cycles[1].BootTime)
+	assert.Equal([]string{"4", "3", "5"}, testEventIDs(cycles[0].OldestToNewest()))
+	assert.Equal([]string{"5", "3", "4"}, testEventIDs(cycles[0].NewestToOldest()))
+	assert.Equal([]string{"1", "2"}, testEventIDs(cycles[1].OldestToNewest()))
+	assert.Empty(SplitIntoCycles(nil))
+}
+
+func TestSplitIntoCyclesWithTolerance(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+		testCycleEvent("2", "offline", bootTime1+1, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("3", "online", bootTime2+2, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("4", "operational", bootTime2, now.Add(-40*time.Minute), "b"),
+	}
+
+	assert := assert.New(t)
+	assert.Len(SplitIntoCycles(events), 4)
+	cycles := SplitIntoCycles(events, WithBootTimeTolerance(2*time.Second))
+	assert.Len(cycles, 2)
+	assert.Equal(bootTime2, cycles[0].BootTime)
+	assert.Equal(bootTime1, cycles[1].BootTime)
+	assert.Equal([]string{"3", "4"}, testEventIDs(cycles[0].OldestToNewest()))
+	assert.Equal([]string{"1", "2"}, testEventIDs(cycles[1].OldestToNewest()))
+	assert.Len(Reboots(events, WithBootTimeTolerance(2*time.Second)), 1)
+}
+
+func TestCycle(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime := now.Add(-1 * time.Hour).Unix()
+	cycle := SplitIntoCycles([]interpreter.Event{
+		testCycleEvent("1", "online", bootTime, now.Add(-50*time.Minute), "a"),
+		testCycleEvent("2", "offline", bootTime, now.Add(-40*time.Minute), "a"),
+		testCycleEvent("3", "online", bootTime, now.Add(-30*time.Minute), "b"),
+		testCycleEvent("4", "operational", bootTime, now.Add(-20*time.Minute), ""),
+		interpreter.Event{
+			TransactionUUID: "5",
+			Destination:     "random-destination",
+			Metadata:        map[string]string{interpreter.BootTimeKey: fmt.Sprint(bootTime)},
+			Birthdate:       now.Add(-10 * time.Minute).UnixNano(),
+		},
+	})[0]
+
+	assert.Equal(5, cycle.Len())
+	assert.Equal(now.Add(-50*time.Minute).UnixNano(), cycle.FirstBirthdate().UnixNano())
+	assert.Equal(now.Add(-10*time.Minute).UnixNano(), cycle.LastBirthdate().UnixNano())
+	assert.Equal(40*time.Minute, cycle.Duration())
+	assert.Equal([]string{"a", "b"}, cycle.Sessions())
+	assert.Equal(map[string]int{"online": 2, "offline": 1, "operational": 1}, cycle.EventTypeCounts())
+
+	var empty Cycle
+	assert.Equal(0, empty.Len())
+	assert.True(empty.FirstBirthdate().IsZero())
+	assert.True(empty.LastBirthdate().IsZero())
+	assert.Equal(time.Duration(0), empty.Duration())
+	assert.Empty(empty.Sessions())
+	assert.Empty(empty.NewestToOldest())
+}
+
+func testCycleEvent(id string, eventType string, bootTime int64, birthdate time.Time, sessionID string) interpreter.Event {
+	return interpreter.Event{
+		TransactionUUID: id,
+		Destination:     fmt.Sprintf("event:device-status/mac:112233445566/%s", eventType),
+		Metadata:        map[string]string{interpreter.BootTimeKey: fmt.Sprint(bootTime)},
+		Birthdate:       birthdate.UnixNano(),
+		SessionID:       sessionID,
+	}
+}
+
+func testEventIDs(events []interpreter.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.TransactionUUID)
+	}
+
+	return ids
+}
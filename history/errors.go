@@ -65,6 +65,12 @@ func (e ComparatorErr) Event() interpreter.Event {
 	return e.ComparisonEvent
 }
 
+// Explanation implements the Explainer interface, explaining the error's tag
+// and including the event in history that caused the error.
+func (e ComparatorErr) Explanation() string {
+	return fmt.Sprintf("%s\n\nComparison event:\n%s", validation.Explain(e.Tag()), validation.FormatEvents(e.ComparisonEvent))
+}
+
 // EventFinderErr is an error used by EventFinder.
 type EventFinderErr struct {
 	OriginalErr error
@@ -103,6 +109,10 @@ type CycleValidationErr struct {
 	ErrorTag          validation.Tag
 	ErrorDetailKey    string
 	ErrorDetailValues []string
+
+	// OffendingEvents are the events that caused the error, so that callers do not
+	// have to search the list of events again to find them.
+	OffendingEvents []interpreter.Event
 }
 
 func (e CycleValidationErr) Error() string {
@@ -136,6 +146,36 @@ func (e CycleValidationErr) Fields() []string {
 	return e.ErrorDetailValues
 }
 
+// Events implements the ErrorWithEvents interface, returning the events that caused the error.
+func (e CycleValidationErr) Events() []interpreter.Event {
+	return e.OffendingEvents
+}
+
+// TransactionUUIDs returns the transaction uuids of the events that caused the error.
+func (e CycleValidationErr) TransactionUUIDs() []string {
+	ids := make([]string, 0, len(e.OffendingEvents))
+	for _, event := range e.OffendingEvents {
+		ids = append(ids, event.TransactionUUID)
+	}
+
+	return ids
+}
+
+// Explanation implements the Explainer interface, explaining the error's tag
+// and including the details and the events that caused the error.
+func (e CycleValidationErr) Explanation() string {
+	explanation := validation.Explain(e.Tag()).String()
+	if len(e.ErrorDetailValues) > 0 {
+		explanation = fmt.Sprintf("%s\n\nDetails:\n%s", explanation, e.ErrorDetails())
+	}
+
+	if len(e.OffendingEvents) > 0 {
+		explanation = fmt.Sprintf("%s\n\nOffending events:\n%s", explanation, validation.FormatEvents(e.OffendingEvents...))
+	}
+
+	return explanation
+}
+
 // ErrorDetails returns the ErrorCauseKey and ErrorCauseValues in a string.
 func (e CycleValidationErr) ErrorDetails() string {
 	var output strings.Builder
@@ -156,3 +196,24 @@ func (e CycleValidationErr) ErrorDetails() string {
 	output.WriteRune(']')
 	return output.String()
 }
+
+// uniqueEvents removes repeats of the same event from a list of events, keeping the first of each.
+func uniqueEvents(events []interpreter.Event) []interpreter.Event {
+	type eventKey struct {
+		id          string
+		birthdate   int64
+		destination string
+	}
+
+	seen := make(map[eventKey]bool, len(events))
+	var unique []interpreter.Event
+	for _, event := range events {
+		key := eventKey{id: event.TransactionUUID, birthdate: event.Birthdate, destination: event.Destination}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, event)
+		}
+	}
+
+	return unique
+}
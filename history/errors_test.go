@@ -1,7 +1,10 @@
 package history
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/xmidt-org/interpreter/validation"
 
@@ -106,6 +109,7 @@ func TestCycleValidationErr(t *testing.T) {
 		expectedTag          validation.Tag
 		expectedFields       []string
 		expectedDetailString string
+		expectedIDs          []string
 	}{
 		{
 			description:          "No underlying error or fields",
@@ -134,6 +138,17 @@ func TestCycleValidationErr(t *testing.T) {
 			expectedFields:       []string{"test", "test2"},
 			expectedDetailString: "descriptive key: [test, test2]",
 		},
+		{
+			description: "With Offending Events",
+			err: CycleValidationErr{
+				OriginalErr:     testErr,
+				OffendingEvents: []interpreter.Event{{TransactionUUID: "1"}, {TransactionUUID: "2"}},
+			},
+			expectedErr:          testErr,
+			expectedTag:          testTag,
+			expectedDetailString: "unknown: []",
+			expectedIDs:          []string{"1", "2"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -147,7 +162,372 @@ func TestCycleValidationErr(t *testing.T) {
 			assert.Equal(tc.expectedTag, tc.err.Tag())
 			assert.ElementsMatch(tc.expectedFields, tc.err.Fields())
 			assert.Contains(tc.err.ErrorDetails(), tc.expectedDetailString)
+			assert.Equal(tc.err.OffendingEvents, tc.err.Events())
+			assert.ElementsMatch(tc.expectedIDs, tc.err.TransactionUUIDs())
 		})
 	}
 
 }
+
+func TestComparatorErrExplanation(t *testing.T) {
+	assert := assert.New(t)
+	event := interpreter.Event{TransactionUUID: "test-id", Destination: "event:device-status/mac:112233445566/online"}
+	err := ComparatorErr{OriginalErr: errNewerBootTime, ErrorTag: validation.NewerBootTimeFound, ComparisonEvent: event}
+	explanation := err.Explanation()
+	assert.Contains(explanation, validation.Explain(validation.NewerBootTimeFound).String())
+	assert.Contains(explanation, validation.FormatEvents(event))
+}
+
+func TestCycleValidationErrExplanation(t *testing.T) {
+	assert := assert.New(t)
+	err := CycleValidationErr{OriginalErr: ErrRepeatID, ErrorTag: validation.RepeatedTransactionUUID}
+	assert.Equal(validation.Explain(validation.RepeatedTransactionUUID).String(), err.Explanation())
+
+	err.ErrorDetailKey = "repeated uuids"
+	err.ErrorDetailValues = []string{"1", "2"}
+	explanation := err.Explanation()
+	assert.Contains(explanation, validation.Explain(validation.RepeatedTransactionUUID).String())
+	assert.Contains(explanation, "repeated uuids: [1, 2]")
+	assert.NotContains(explanation, "Offending events")
+
+	events := []interpreter.Event{
+		{TransactionUUID: "1", Destination: "event:device-status/mac:112233445566/online"},
+		{TransactionUUID: "1", Destination: "event:device-status/mac:112233445566/offline"},
+	}
+	err.OffendingEvents = events
+	explanation = err.Explanation()
+	assert.Contains(explanation, "repeated uuids: [1, 2]")
+	assert.Contains(explanation, validation.FormatEvents(events...))
+}
+
+func TestUniqueEvents(t *testing.T) {
+	events := []interpreter.Event{
+		{TransactionUUID: "1", Birthdate: 1},
+		{TransactionUUID: "2", Birthdate: 2},
+		{TransactionUUID: "1", Birthdate: 1},
+		{TransactionUUID: "1", Birthdate: 3},
+	}
+
+	assert.Equal(t, []interpreter.Event{events[0], events[1], events[3]}, uniqueEvents(events))
+	assert.Empty(t, uniqueEvents(nil))
+}
+
+func TestOffendingEvents(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	event := func(id string, eventType string, bootTime int64, minutesAgo int, sessionID string) interpreter.Event {
+		return testCycleEvent(id, eventType, bootTime, now.Add(-time.Duration(minutesAgo)*time.Minute), sessionID)
+	}
+	withMetadata := func(e interpreter.Event, key string, value string) interpreter.Event {
+		e.Metadata[key] = value
+		return e
+	}
+
+	// one boot cycle per firmware name, an hour apart
+	firmwareEvents := func(names ...string) []interpreter.Event {
+		var events []interpreter.Event
+		for i, name := range names {
+			bootTime := now.Add(time.Duration(i-len(names)) * time.Hour)
+			e := testCycleEvent(name, "online", bootTime.Unix(), bootTime.Add(time.Minute), name)
+			e.Metadata[FirmwareNameKey] = name
+			e.Metadata[ModelKey] = "model-1"
+			events = append(events, e)
+		}
+		return events
+	}
+
+	mandatoryValidator, err := MandatoryFirmwareValidator(FirmwareParser{}, []FirmwareRule{{Mandatory: []string{"2.0"}}})
+	assert.Nil(t, err)
+	rangeValidator, err := FirmwareRangeValidator(FirmwareParser{}, []FirmwareRule{{Max: "2.0"}})
+	assert.Nil(t, err)
+	sequence, err := ParseSequencePattern("online operational fully-manageable")
+	assert.Nil(t, err)
+
+	rebootEvents := func(count int) []interpreter.Event {
+		var events []interpreter.Event
+		for i := 0; i < count; i++ {
+			bootTime := now.Add(time.Duration(i-10) * 10 * time.Minute)
+			events = append(events, testCycleEvent(fmt.Sprintf("r%d", i), "online", bootTime.Unix(), bootTime.Add(time.Minute), fmt.Sprintf("r%d", i)))
+		}
+		return events
+	}
+
+	reconnectEvents := func(count int) []interpreter.Event {
+		var events []interpreter.Event
+		for i := 0; i < count; i++ {
+			events = append(events, event(fmt.Sprintf("s%d", i), "online", bootTime1, 110-5*i, fmt.Sprintf("s%d", i)))
+		}
+		return events
+	}
+
+	scheduledAt := now.Add(-6 * time.Hour)
+	scheduled := scheduledAt.Add(10 * time.Minute)
+	tests := []struct {
+		description string
+		validator   CycleValidator
+		events      []interpreter.Event
+		expectedIDs []string
+	}{
+		{
+			description: "repeated transaction uuids",
+			validator:   TransactionUUIDValidator(),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "operational", bootTime1, 100, "a"),
+				event("1", "fully-manageable", bootTime1, 90, "a"),
+				event("3", "offline", bootTime1, 80, "a"),
+				event("2", "online", bootTime2, 50, "b"),
+			},
+			expectedIDs: []string{"1", "2", "1", "2"},
+		},
+		{
+			description: "broken event order",
+			validator:   EventOrderValidator([]string{"online", "operational", "fully-manageable"}),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "offline", bootTime1, 100, "a"),
+				event("3", "operational", bootTime1, 90, "a"),
+				event("4", "fully-manageable", bootTime1, 80, "a"),
+			},
+			expectedIDs: []string{"2"},
+		},
+		{
+			description: "incomplete event order",
+			validator:   EventOrderValidator([]string{"online", "operational", "fully-manageable"}),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "operational", bootTime1, 100, "a"),
+			},
+			expectedIDs: []string{"1", "2"},
+		},
+		{
+			description: "inconsistent metadata in the first event",
+			validator:   MetadataValidator([]string{"hw-mac"}, false),
+			events: []interpreter.Event{
+				withMetadata(event("1", "online", bootTime1, 110, "a"), "hw-mac", "mac-2"),
+				withMetadata(event("2", "operational", bootTime1, 100, "a"), "hw-mac", "mac-1"),
+				withMetadata(event("3", "online", bootTime2, 50, "b"), "hw-mac", "mac-1"),
+			},
+			expectedIDs: []string{"1"},
+		},
+		{
+			description: "inconsistent metadata without a majority",
+			validator:   MetadataValidator([]string{"hw-mac"}, false),
+			events: []interpreter.Event{
+				withMetadata(event("1", "online", bootTime1, 110, "a"), "hw-mac", "mac-1"),
+				withMetadata(event("2", "online", bootTime2, 50, "b"), "hw-mac", "mac-2"),
+			},
+			expectedIDs: []string{"1", "2"},
+		},
+		{
+			description: "inconsistent metadata within cycles",
+			validator:   MetadataValidator([]string{"fw-name"}, true),
+			events: []interpreter.Event{
+				withMetadata(event("1", "online", bootTime1, 110, "a"), "fw-name", "fw-2"),
+				withMetadata(event("2", "operational", bootTime1, 100, "a"), "fw-name", "fw-1"),
+				withMetadata(event("3", "offline", bootTime1, 90, "a"), "fw-name", "fw-1"),
+				withMetadata(event("4", "online", bootTime2, 50, "b"), "fw-name", "fw-2"),
+				withMetadata(event("5", "operational", bootTime2, 40, "b"), "fw-name", "fw-2"),
+				withMetadata(event("6", "fully-manageable", bootTime2, 30, "b"), "fw-name", "fw-3"),
+			},
+			expectedIDs: []string{"1", "6"},
+		},
+		{
+			description: "false reboot",
+			validator:   TrueRebootValidator(),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "operational", bootTime1, 100, "a"),
+				event("3", "online", bootTime1, 50, "b"),
+			},
+			expectedIDs: []string{"3", "2"},
+		},
+		{
+			description: "missing online event",
+			validator:   SessionOnlineValidator(nil),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "offline", bootTime1, 100, "a"),
+				event("3", "operational", bootTime1, 90, "b"),
+				event("4", "offline", bootTime1, 80, "b"),
+			},
+			expectedIDs: []string{"3", "4"},
+		},
+		{
+			description: "session with two boot-times",
+			validator:   SessionBootTimeValidator(nil),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "offline", bootTime1, 100, "a"),
+				event("3", "online", bootTime1, 90, "b"),
+				event("4", "operational", bootTime2, 50, "b"),
+			},
+			expectedIDs: []string{"3", "4"},
+		},
+		{
+			description: "session started while open",
+			validator:   SessionStartValidator(nil),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "operational", bootTime1, 100, "a"),
+				event("3", "online", bootTime1, 90, "b"),
+				event("4", "offline", bootTime1, 80, "b"),
+				event("5", "online", bootTime2, 50, "c"),
+			},
+			expectedIDs: []string{"1", "2", "3", "4"},
+		},
+		{
+			description: "boot-time regression",
+			validator:   BootTimeRegressionValidator(),
+			events: []interpreter.Event{
+				event("1", "online", bootTime2, 50, "a"),
+				event("2", "online", bootTime1, 40, "b"),
+				event("3", "online", bootTime2, 30, "c"),
+			},
+			expectedIDs: []string{"2"},
+		},
+		{
+			description: "clock reset",
+			validator:   ClockResetValidator(),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "online", 100, 50, "b"),
+			},
+			expectedIDs: []string{"2"},
+		},
+		{
+			description: "silent boot-time change",
+			validator:   BootTimeChangeValidator(),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "operational", bootTime1, 100, "a"),
+				event("3", "operational", bootTime2, 50, "a"),
+			},
+			expectedIDs: []string{"2", "3"},
+		},
+		{
+			description: "illegal transitions sharing an event",
+			validator:   StateMachineValidator(nil),
+			events: []interpreter.Event{
+				event("3", "online", bootTime1, 90, "c"),
+				event("2", "online", bootTime1, 100, "b"),
+				event("1", "online", bootTime1, 110, "a"),
+			},
+			expectedIDs: []string{"1", "2", "3"},
+		},
+		{
+			description: "gaps sharing events",
+			validator:   GapValidator(15*time.Minute, []GapLimit{{From: "online", To: "operational", MaxGap: 15 * time.Minute}}),
+			events: []interpreter.Event{
+				event("4", "offline", bootTime1, 10, "a"),
+				event("3", "fully-manageable", bootTime1, 80, "a"),
+				event("2", "operational", bootTime1, 90, "a"),
+				event("1", "online", bootTime1, 110, "a"),
+			},
+			expectedIDs: []string{"1", "2", "3", "4"},
+		},
+		{
+			description: "near duplicates",
+			validator:   NearDuplicateValidator(5*time.Minute, EventTypeKey()),
+			events: []interpreter.Event{
+				event("3", "operational", bootTime1, 90, "a"),
+				event("2", "online", bootTime1, 108, "a"),
+				event("1", "online", bootTime1, 110, "a"),
+			},
+			expectedIDs: []string{"1", "2"},
+		},
+		{
+			description: "sequence deviation",
+			validator:   SequenceValidator(sequence),
+			events: []interpreter.Event{
+				event("3", "offline", bootTime1, 90, "a"),
+				event("2", "operational", bootTime1, 100, "a"),
+				event("1", "online", bootTime1, 110, "a"),
+			},
+			expectedIDs: []string{"3"},
+		},
+		{
+			description: "sequence ended early",
+			validator:   SequenceValidator(sequence),
+			events: []interpreter.Event{
+				event("2", "operational", bootTime1, 100, "a"),
+				event("1", "online", bootTime1, 110, "a"),
+			},
+			expectedIDs: []string{"1", "2"},
+		},
+		{
+			description: "firmware downgrade",
+			validator:   FirmwareDowngradeValidator(FirmwareParser{}),
+			events:      firmwareEvents("fw_1.1", "fw_2.0", "fw_1.2"),
+			expectedIDs: []string{"fw_2.0", "fw_1.2"},
+		},
+		{
+			description: "skipped mandatory firmware",
+			validator:   mandatoryValidator,
+			events:      firmwareEvents("fw_1.1", "fw_2.6"),
+			expectedIDs: []string{"fw_1.1", "fw_2.6"},
+		},
+		{
+			description: "firmware out of range",
+			validator:   rangeValidator,
+			events:      firmwareEvents("fw_1.1", "fw_2.6", "fw_3.0"),
+			expectedIDs: []string{"fw_2.6", "fw_3.0"},
+		},
+		{
+			description: "metadata changes sharing an event",
+			validator:   MetadataPolicyValidator([]MetadataPolicy{{Key: "hw-mac", Scope: ChangeNever}}),
+			events: []interpreter.Event{
+				withMetadata(event("1", "online", bootTime1, 110, "a"), "hw-mac", "mac-1"),
+				withMetadata(event("2", "online", bootTime1, 90, "b"), "hw-mac", "mac-2"),
+				withMetadata(event("3", "online", bootTime2, 50, "c"), "hw-mac", "mac-3"),
+			},
+			expectedIDs: []string{"1", "2", "3"},
+		},
+		{
+			description: "reboot reason mismatch",
+			validator:   RebootReasonValidator(RebootPolicy{}),
+			events: []interpreter.Event{
+				event("1", "online", bootTime1, 110, "a"),
+				event("2", "offline", bootTime1, 70, "a"),
+				withMetadata(event("3", "online", bootTime2, 50, "b"), DefaultRebootReasonKey, "scheduled"),
+			},
+			expectedIDs: []string{"3", "2"},
+		},
+		{
+			description: "reboot storm",
+			validator:   RebootFrequencyValidator(RebootFrequency{Window: 30 * time.Minute, MaxReboots: 2}),
+			events:      rebootEvents(5),
+			expectedIDs: []string{"r1", "r2", "r3", "r4"},
+		},
+		{
+			description: "connection flapping",
+			validator:   RebootFrequencyValidator(RebootFrequency{Window: 15 * time.Minute, MaxReconnects: 3}),
+			events:      reconnectEvents(6),
+			expectedIDs: []string{"s1", "s2", "s3", "s4", "s5"},
+		},
+		{
+			description: "reboot schedule deviation",
+			validator:   RebootScheduleValidator(5 * time.Minute),
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", now.Add(-10*time.Hour).Unix(), now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", fmt.Sprintf("reboot-pending/%d/10m", scheduledAt.Unix()), now.Add(-10*time.Hour).Unix(), scheduledAt, "a"),
+				testCycleEvent("3", "offline", now.Add(-10*time.Hour).Unix(), scheduled.Add(-8*time.Minute), "a"),
+				testCycleEvent("4", "online", scheduled.Add(2*time.Minute).Unix(), scheduled.Add(3*time.Minute), "b"),
+			},
+			expectedIDs: []string{"2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			valid, err := tc.validator.Valid(tc.events)
+			assert.False(valid)
+			var cvErr CycleValidationErr
+			assert.True(errors.As(err, &cvErr))
+			assert.Equal(tc.expectedIDs, cvErr.TransactionUUIDs())
+		})
+	}
+}
@@ -51,6 +51,51 @@ func DefaultComparator() ComparatorFunc {
 	}
 }
 
+// HistoryComparator is an optional interface for Comparators whose result depends on the rest of the history,
+// such as comparators that treat boot-times within the boot-time tolerance as the same. The parsers call ForHistory
+// once with the history being parsed and compare events with the returned Comparator.
+type HistoryComparator interface {
+	Comparator
+	ForHistory(events []interpreter.Event, currentEvent interpreter.Event) Comparator
+}
+
+// forHistory returns the Comparator to use for a history, which is the comparator itself unless it
+// implements HistoryComparator.
+func forHistory(comparator Comparator, events []interpreter.Event, currentEvent interpreter.Event) Comparator {
+	if historyComparator, ok := comparator.(HistoryComparator); ok {
+		return historyComparator.ForHistory(events, currentEvent)
+	}
+
+	return comparator
+}
+
+// BootTimeComparator is a Comparator that compares boot-times after normalizing them with the boot-time clusters
+// of the history they are in, so that it agrees with the parsers on which boot-times belong to the same boot cycle.
+// When used on its own, the clusters are built from the two events being compared. See OlderBootTimeHistoryComparator
+// and DuplicateEventHistoryComparator.
+type BootTimeComparator struct {
+	o        options
+	compare  func(clusters BootTimeClusters, baseEvent interpreter.Event, newEvent interpreter.Event) (bool, error)
+	clusters *BootTimeClusters
+}
+
+// Compare implements the Comparator interface.
+func (c BootTimeComparator) Compare(baseEvent interpreter.Event, newEvent interpreter.Event) (bool, error) {
+	if c.clusters != nil {
+		return c.compare(*c.clusters, baseEvent, newEvent)
+	}
+
+	return c.compare(c.o.clusterBootTimes(nil, baseEvent, newEvent), baseEvent, newEvent)
+}
+
+// ForHistory implements the HistoryComparator interface, returning a copy of the comparator that
+// uses the boot-time clusters of the history.
+func (c BootTimeComparator) ForHistory(events []interpreter.Event, currentEvent interpreter.Event) Comparator {
+	clusters := c.o.clusterBootTimes(events, currentEvent)
+	c.clusters = &clusters
+	return c
+}
+
 // Comparators are a list of objects that implement the Comparator interface
 type Comparators []Comparator
 
@@ -65,67 +110,98 @@ func (c Comparators) Compare(baseEvent interpreter.Event, newEvent interpreter.E
 	return false, nil
 }
 
+// ForHistory implements the HistoryComparator interface, calling ForHistory on each comparator that implements it.
+func (c Comparators) ForHistory(events []interpreter.Event, currentEvent interpreter.Event) Comparator {
+	comparators := make(Comparators, 0, len(c))
+	for _, comparator := range c {
+		comparators = append(comparators, forHistory(comparator, events, currentEvent))
+	}
+
+	return comparators
+}
+
 // OlderBootTimeComparator returns a ComparatorFunc to check and see if newEvent's boot-time is
 // less than the baseEvent's boot-time. If it is, it returns true and an error.
+// Boot-times within the boot-time tolerance of each other are considered equal.
 // OlderBootTimeComparator assumes that newEvent has a valid boot-time
 // and does not do any error-checking of newEvent's boot-time.
-func OlderBootTimeComparator() ComparatorFunc {
-	return func(baseEvent interpreter.Event, newEvent interpreter.Event) (bool, error) {
-		// baseEvent is newEvent, no need to compare boot-times
-		if baseEvent.TransactionUUID == newEvent.TransactionUUID {
-			return false, nil
-		}
+func OlderBootTimeComparator(opts ...Option) ComparatorFunc {
+	return OlderBootTimeHistoryComparator(opts...).Compare
+}
 
-		latestBootTime, _ := newEvent.BootTime()
-		bootTime, err := baseEvent.BootTime()
-		if err != nil || bootTime <= 0 {
-			return false, nil
-		}
+// OlderBootTimeHistoryComparator returns a BootTimeComparator that works like OlderBootTimeComparator,
+// except that the parsers compare boot-times by the boot-time clusters of the whole history, so that
+// boot-times chained together by the boot-time tolerance are considered equal.
+func OlderBootTimeHistoryComparator(opts ...Option) BootTimeComparator {
+	return BootTimeComparator{
+		o: newOptions(opts),
+		compare: func(clusters BootTimeClusters, baseEvent interpreter.Event, newEvent interpreter.Event) (bool, error) {
+			// baseEvent is newEvent, no need to compare boot-times
+			if baseEvent.TransactionUUID == newEvent.TransactionUUID {
+				return false, nil
+			}
 
-		// if this event has a boot-time more recent than the latest one, return an error
-		if bootTime > latestBootTime {
-			return true, ComparatorErr{OriginalErr: errNewerBootTime, ErrorTag: validation.NewerBootTimeFound, ComparisonEvent: baseEvent}
-		}
+			latestBootTime, _ := clusters.BootTime(newEvent)
+			bootTime, err := clusters.BootTime(baseEvent)
+			if err != nil || bootTime <= 0 {
+				return false, nil
+			}
 
-		return false, nil
+			// if this event has a boot-time more recent than the latest one, return an error
+			if bootTime > latestBootTime {
+				return true, ComparatorErr{OriginalErr: errNewerBootTime, ErrorTag: validation.NewerBootTimeFound, ComparisonEvent: baseEvent}
+			}
 
+			return false, nil
+		},
 	}
 }
 
 // DuplicateEventComparator returns a ComparatorFunc to check and see if newEvent is a duplicate. A duplicate event
 // in this case is defined as sharing the same event type and boot-time as the base event while having a birthdate
-// that is equal to or newer than baseEvent's birthdate. If newEvent is found to be a duplicate, it returns true and
+// that is equal to or newer than baseEvent's birthdate. Boot-times within the boot-time tolerance of each other
+// are considered the same. If newEvent is found to be a duplicate, it returns true and
 // an error. It assumes that newEvent has a valid boot-time and event-type and does not do any error-checking
 // of newEvent's boot-time or event type.
-func DuplicateEventComparator() ComparatorFunc {
-	return func(baseEvent interpreter.Event, newEvent interpreter.Event) (bool, error) {
-		// baseEvent is newEvent, no need to compare boot-times
-		if baseEvent.TransactionUUID == newEvent.TransactionUUID {
-			return false, nil
-		}
-
-		baseEventType, err := baseEvent.EventType()
-		if err != nil {
-			return false, nil
-		}
+func DuplicateEventComparator(opts ...Option) ComparatorFunc {
+	return DuplicateEventHistoryComparator(opts...).Compare
+}
 
-		newEventType, _ := newEvent.EventType()
+// DuplicateEventHistoryComparator returns a BootTimeComparator that works like DuplicateEventComparator,
+// except that the parsers compare boot-times by the boot-time clusters of the whole history, so that
+// boot-times chained together by the boot-time tolerance are considered the same.
+func DuplicateEventHistoryComparator(opts ...Option) BootTimeComparator {
+	return BootTimeComparator{
+		o: newOptions(opts),
+		compare: func(clusters BootTimeClusters, baseEvent interpreter.Event, newEvent interpreter.Event) (bool, error) {
+			// baseEvent is newEvent, no need to compare boot-times
+			if baseEvent.TransactionUUID == newEvent.TransactionUUID {
+				return false, nil
+			}
 
-		// see if event types match
-		if strings.EqualFold(strings.TrimSpace(baseEventType), strings.TrimSpace(newEventType)) {
-			latestBootTime, _ := newEvent.BootTime()
-			bootTime, err := baseEvent.BootTime()
-			if err != nil || bootTime <= 0 {
+			baseEventType, err := baseEvent.EventType()
+			if err != nil {
 				return false, nil
 			}
 
-			// If the boot-time is the same as the latestBootTime, and the birthdate is older or equal,
-			// this means that newEvent is a duplicate.
-			if bootTime == latestBootTime && baseEvent.Birthdate <= newEvent.Birthdate {
-				return true, ComparatorErr{OriginalErr: errDuplicateEvent, ErrorTag: validation.DuplicateEvent, ComparisonEvent: baseEvent}
+			newEventType, _ := newEvent.EventType()
+
+			// see if event types match
+			if strings.EqualFold(strings.TrimSpace(baseEventType), strings.TrimSpace(newEventType)) {
+				latestBootTime, _ := clusters.BootTime(newEvent)
+				bootTime, err := clusters.BootTime(baseEvent)
+				if err != nil || bootTime <= 0 {
+					return false, nil
+				}
+
+				// If the boot-time is the same as the latestBootTime, and the birthdate is older or equal,
+				// this means that newEvent is a duplicate.
+				if bootTime == latestBootTime && baseEvent.Birthdate <= newEvent.Birthdate {
+					return true, ComparatorErr{OriginalErr: errDuplicateEvent, ErrorTag: validation.DuplicateEvent, ComparisonEvent: baseEvent}
+				}
 			}
-		}
 
-		return false, nil
+			return false, nil
+		},
 	}
 }
@@ -297,3 +297,100 @@ func TestDuplicateEventComparator(t *testing.T) {
 		})
 	}
 }
+
+func TestComparatorsWithTolerance(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	incomingEvent := interpreter.Event{
+		Destination:     "event:device-status/mac:112233445566/online",
+		Metadata:        map[string]string{interpreter.BootTimeKey: fmt.Sprint(now.Unix())},
+		TransactionUUID: "123",
+		Birthdate:       now.UnixNano(),
+	}
+
+	historyEvent := interpreter.Event{
+		Destination:     "event:device-status/mac:112233445566/online",
+		Metadata:        map[string]string{interpreter.BootTimeKey: fmt.Sprint(now.Add(time.Second).Unix())},
+		TransactionUUID: "abc",
+		Birthdate:       now.Add(-1 * time.Minute).UnixNano(),
+	}
+
+	assert := assert.New(t)
+	match, err := OlderBootTimeComparator().Compare(historyEvent, incomingEvent)
+	assert.True(match)
+	assert.True(errors.Is(err, errNewerBootTime))
+	match, err = OlderBootTimeComparator(WithBootTimeTolerance(time.Second)).Compare(historyEvent, incomingEvent)
+	assert.False(match)
+	assert.Nil(err)
+
+	match, err = DuplicateEventComparator().Compare(historyEvent, incomingEvent)
+	assert.False(match)
+	assert.Nil(err)
+	match, err = DuplicateEventComparator(WithBootTimeTolerance(time.Second)).Compare(historyEvent, incomingEvent)
+	assert.True(match)
+	assert.True(errors.Is(err, errDuplicateEvent))
+}
+
+func TestBootTimeComparatorForHistory(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-1 * time.Hour).Unix()
+	// boot-times 2s apart are chained into one cluster, even though the first and last are 4s apart
+	currentEvent := testCycleEvent("1", "online", bootTime, now.Add(-30*time.Minute), "a")
+	events := []interpreter.Event{
+		currentEvent,
+		testCycleEvent("2", "online", bootTime+2, now.Add(-40*time.Minute), "a"),
+		testCycleEvent("3", "online", bootTime+4, now.Add(-50*time.Minute), "a"),
+	}
+
+	opts := []Option{WithBootTimeTolerance(2 * time.Second)}
+	tests := []struct {
+		description        string
+		comparator         Comparator
+		expectedPairwise   error
+		expectedForHistory error
+	}{
+		{
+			description:      "older boot-time",
+			comparator:       OlderBootTimeHistoryComparator(opts...),
+			expectedPairwise: errNewerBootTime,
+		},
+		{
+			description:        "duplicate event",
+			comparator:         DuplicateEventHistoryComparator(opts...),
+			expectedForHistory: errDuplicateEvent,
+		},
+		{
+			description:      "comparators",
+			comparator:       Comparators{OlderBootTimeHistoryComparator(opts...)},
+			expectedPairwise: errNewerBootTime,
+		},
+		{
+			description:        "pairwise older boot-time",
+			comparator:         OlderBootTimeComparator(opts...),
+			expectedPairwise:   errNewerBootTime,
+			expectedForHistory: errNewerBootTime,
+		},
+		{
+			description: "pairwise duplicate event",
+			comparator:  DuplicateEventComparator(opts...),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			match, err := tc.comparator.Compare(events[2], currentEvent)
+			assert.Equal(tc.expectedPairwise != nil, match)
+			assert.True(errors.Is(err, tc.expectedPairwise))
+
+			match, err = forHistory(tc.comparator, events, currentEvent).Compare(events[2], currentEvent)
+			assert.Equal(tc.expectedForHistory != nil, match)
+			assert.True(errors.Is(err, tc.expectedForHistory))
+		})
+	}
+
+	cycle, err := CurrentCycleParser(OlderBootTimeHistoryComparator(opts...), opts...).Parse(events, currentEvent)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, testEventIDs(cycle))
+}
@@ -28,17 +28,48 @@ var (
 	EventNotFoundErr = errors.New("event not found")
 )
 
+// EventFinder finds an event in a history that is related to the current event.
+type EventFinder interface {
+	Find(events []interpreter.Event, currentEvent interpreter.Event) (interpreter.Event, error)
+}
+
 // FinderFunc is a function type that takes in a slice of events
 // and the current event and returns an Event from the slice.
 type FinderFunc func([]interpreter.Event, interpreter.Event) (interpreter.Event, error)
 
+// Find runs the FinderFunc, making a FinderFunc an EventFinder.
 func (f FinderFunc) Find(events []interpreter.Event, currentEvent interpreter.Event) (interpreter.Event, error) {
 	return f(events, currentEvent)
 }
 
+// Finders are a list of objects that implement the EventFinder interface.
+type Finders []EventFinder
+
+// Find tries each finder in order and returns the event from the first finder that finds one.
+// If no finder finds an event, the errors from every finder are returned.
+func (f Finders) Find(events []interpreter.Event, currentEvent interpreter.Event) (interpreter.Event, error) {
+	var allErrors validation.Errors
+	for _, finder := range f {
+		event, err := finder.Find(events, currentEvent)
+		if err == nil {
+			return event, nil
+		}
+
+		allErrors = append(allErrors, err)
+	}
+
+	if len(allErrors) == 0 {
+		return interpreter.Event{}, EventFinderErr{OriginalErr: EventNotFoundErr}
+	}
+
+	return interpreter.Event{}, allErrors
+}
+
 // LastSessionFinder returns a function to find an event that is deemed valid by the Validator passed in
-// with the boot-time of the previous session.
-func LastSessionFinder(validator validation.Validator) FinderFunc {
+// with the boot-time of the previous session. Boot-times within the boot-time tolerance of each other
+// are considered the same.
+func LastSessionFinder(validator validation.Validator, opts ...Option) FinderFunc {
+	o := newOptions(opts)
 	return func(events []interpreter.Event, currentEvent interpreter.Event) (interpreter.Event, error) {
 		// verify that the current event has a boot-time
 		currentBootTime, err := currentEvent.BootTime()
@@ -46,7 +77,7 @@ func LastSessionFinder(validator validation.Validator) FinderFunc {
 			return interpreter.Event{}, validation.InvalidBootTimeErr{OriginalErr: err}
 		}
 
-		event, found := lastSessionFinder(events, currentEvent, validator)
+		event, found := lastSessionFinder(events, currentEvent, validator, o.clusterBootTimes(events, currentEvent))
 		// final check to make sure that we actually found an event
 		if !found {
 			return interpreter.Event{}, EventFinderErr{OriginalErr: EventNotFoundErr}
@@ -55,8 +86,8 @@ func LastSessionFinder(validator validation.Validator) FinderFunc {
 	}
 }
 
-func lastSessionFinder(events []interpreter.Event, currentEvent interpreter.Event, validator validation.Validator) (interpreter.Event, bool) {
-	currentBootTime, _ := currentEvent.BootTime()
+func lastSessionFinder(events []interpreter.Event, currentEvent interpreter.Event, validator validation.Validator, clusters BootTimeClusters) (interpreter.Event, bool) {
+	currentBootTime, _ := clusters.BootTime(currentEvent)
 
 	var latestEvent interpreter.Event
 	var found bool
@@ -70,13 +101,13 @@ func lastSessionFinder(events []interpreter.Event, currentEvent interpreter.Even
 		}
 
 		// figure out the latest previous boot-time
-		if eBoot, newTime := getPreviousBootTime(event, prevBootTime, currentBootTime); newTime {
+		if eBoot, newTime := getPreviousBootTime(event, prevBootTime, currentBootTime, clusters); newTime {
 			prevBootTime = eBoot
 			found = false
 		}
 
 		// if event does not match validators, continue onto next event.
-		if eventValid := newEventValid(event, latestEvent, validator, prevBootTime); eventValid {
+		if eventValid := newEventValid(event, latestEvent, validator, prevBootTime, clusters); eventValid {
 			latestEvent = event
 			found = true
 		}
@@ -86,8 +117,10 @@ func lastSessionFinder(events []interpreter.Event, currentEvent interpreter.Even
 }
 
 // CurrentSessionFinder returns a function to find an event that is deemed valid by the Validator passed in
-// with the boot-time of the current event.
-func CurrentSessionFinder(validator validation.Validator) FinderFunc {
+// with the boot-time of the current event. Boot-times within the boot-time tolerance of each other
+// are considered the same.
+func CurrentSessionFinder(validator validation.Validator, opts ...Option) FinderFunc {
+	o := newOptions(opts)
 	return func(events []interpreter.Event, currentEvent interpreter.Event) (interpreter.Event, error) {
 		// verify that the current event has a boot-time
 		currentBootTime, err := currentEvent.BootTime()
@@ -95,7 +128,7 @@ func CurrentSessionFinder(validator validation.Validator) FinderFunc {
 			return interpreter.Event{}, validation.InvalidBootTimeErr{OriginalErr: err}
 		}
 
-		event, found := currentSessionFinder(events, currentEvent, validator)
+		event, found := currentSessionFinder(events, currentEvent, validator, o.clusterBootTimes(events, currentEvent))
 		// final check to make sure that we actually found an event
 		if !found {
 			return interpreter.Event{}, EventFinderErr{OriginalErr: EventNotFoundErr}
@@ -104,8 +137,8 @@ func CurrentSessionFinder(validator validation.Validator) FinderFunc {
 	}
 }
 
-func currentSessionFinder(events []interpreter.Event, currentEvent interpreter.Event, validator validation.Validator) (interpreter.Event, bool) {
-	currentBootTime, _ := currentEvent.BootTime()
+func currentSessionFinder(events []interpreter.Event, currentEvent interpreter.Event, validator validation.Validator, clusters BootTimeClusters) (interpreter.Event, bool) {
+	currentBootTime, _ := clusters.BootTime(currentEvent)
 
 	var latestEvent interpreter.Event
 	var found bool
@@ -123,7 +156,7 @@ func currentSessionFinder(events []interpreter.Event, currentEvent interpreter.E
 		}
 
 		// if event does not match validators, continue onto next event.
-		if eventValid := newEventValid(event, latestEvent, validator, currentBootTime); eventValid {
+		if eventValid := newEventValid(event, latestEvent, validator, currentBootTime, clusters); eventValid {
 			latestEvent = event
 			found = true
 		}
@@ -133,11 +166,11 @@ func currentSessionFinder(events []interpreter.Event, currentEvent interpreter.E
 }
 
 // See if event has a boot-time that has greater than the one we are currently tracking but less than
-// the latestBootTime.
-func getPreviousBootTime(event interpreter.Event, currentPrevTime int64, latestBootTime int64) (int64, bool) {
+// the latestBootTime. The event's boot-time is normalized by the clusters passed in.
+func getPreviousBootTime(event interpreter.Event, currentPrevTime int64, latestBootTime int64, clusters BootTimeClusters) (int64, bool) {
 	// Get the bootTime from the event we are checking. If boot-time
 	// doesn't exist, return currentPrevTime, which is the latest previous time currently found.
-	bootTime, _ := event.BootTime()
+	bootTime, _ := clusters.BootTime(event)
 	if bootTime <= 0 {
 		return currentPrevTime, false
 	}
@@ -151,9 +184,10 @@ func getPreviousBootTime(event interpreter.Event, currentPrevTime int64, latestB
 }
 
 // Sees if an event is valid based on the validators passed in and whether it has the targetBootTime.
-func newEventValid(newEvent interpreter.Event, defaultEvent interpreter.Event, validators validation.Validator, targetBootTime int64) bool {
-	bootTime, _ := newEvent.BootTime()
-	currentPrevBootTime, _ := defaultEvent.BootTime()
+// Boot-times are normalized by the clusters passed in.
+func newEventValid(newEvent interpreter.Event, defaultEvent interpreter.Event, validators validation.Validator, targetBootTime int64, clusters BootTimeClusters) bool {
+	bootTime, _ := clusters.BootTime(newEvent)
+	currentPrevBootTime, _ := clusters.BootTime(defaultEvent)
 
 	// if boot-time doesn't match target boot-time, return previous event
 	if bootTime != targetBootTime {
@@ -314,7 +314,7 @@ func TestGetPreviousBootTime(t *testing.T) {
 			event := interpreter.Event{
 				Metadata: map[string]string{interpreter.BootTimeKey: fmt.Sprint(tc.currentTime)},
 			}
-			bootTime, newFound := getPreviousBootTime(event, tc.defaultTime, tc.latestBootTime)
+			bootTime, newFound := getPreviousBootTime(event, tc.defaultTime, tc.latestBootTime, BootTimeClusters{})
 			assert.Equal(tc.expectedTime, bootTime)
 			assert.Equal(tc.expectedNew, newFound)
 		})
@@ -409,8 +409,80 @@ func TestNewValidEvent(t *testing.T) {
 			assert := assert.New(t)
 			val := new(mockValidator)
 			val.On("Valid", tc.newEvent).Return(tc.newEventValid, nil)
-			newEventFound := newEventValid(tc.newEvent, tc.defaultEvent, val, tc.targetBootTime)
+			newEventFound := newEventValid(tc.newEvent, tc.defaultEvent, val, tc.targetBootTime, BootTimeClusters{})
 			assert.Equal(tc.expectedRes, newEventFound)
 		})
 	}
 }
+
+func TestFinders(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	currentEvent := testCycleEvent("current", "online", now.Unix(), now, "a")
+	found := testCycleEvent("found", "online", now.Unix(), now.Add(-1*time.Minute), "a")
+	errNotFound := errors.New("not found")
+	notFound := FinderFunc(func(_ []interpreter.Event, _ interpreter.Event) (interpreter.Event, error) {
+		return interpreter.Event{}, errNotFound
+	})
+	finds := FinderFunc(func(_ []interpreter.Event, _ interpreter.Event) (interpreter.Event, error) {
+		return found, nil
+	})
+
+	tests := []struct {
+		description   string
+		finders       Finders
+		expectedEvent interpreter.Event
+		expectedErrs  int
+	}{
+		{
+			description: "no finders",
+		},
+		{
+			description:   "first finder succeeds",
+			finders:       Finders{finds, notFound},
+			expectedEvent: found,
+		},
+		{
+			description:   "fall back to second finder",
+			finders:       Finders{notFound, finds},
+			expectedEvent: found,
+		},
+		{
+			description:  "all finders fail",
+			finders:      Finders{notFound, notFound},
+			expectedErrs: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			event, err := tc.finders.Find(nil, currentEvent)
+			assert.Equal(tc.expectedEvent, event)
+			if tc.expectedEvent.TransactionUUID != "" {
+				assert.Nil(err)
+				return
+			}
+
+			if tc.expectedErrs == 0 {
+				assert.True(errors.Is(err, EventNotFoundErr))
+				return
+			}
+
+			var errs validation.Errors
+			assert.True(errors.As(err, &errs))
+			assert.Len(errs, tc.expectedErrs)
+			assert.True(errors.Is(errs[0], errNotFound))
+		})
+	}
+}
+
+func TestEventFinderImplementations(t *testing.T) {
+	var finders []EventFinder
+	finders = append(finders,
+		LastSessionFinder(nil),
+		CurrentSessionFinder(nil),
+		Finders{},
+	)
+	assert.Len(t, finders, 3)
+}
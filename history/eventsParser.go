@@ -46,6 +46,11 @@ func bootTimeDescendingSortFunc(events []interpreter.Event) sortFunc {
 	}
 }
 
+// EventsParser returns the events in a history that are relevant to the current event.
+type EventsParser interface {
+	Parse(events []interpreter.Event, currentEvent interpreter.Event) ([]interpreter.Event, error)
+}
+
 // EventsParserFunc is a function that returns the relevant events from a slice of events.
 type EventsParserFunc func([]interpreter.Event, interpreter.Event) ([]interpreter.Event, error)
 
@@ -54,6 +59,22 @@ func (p EventsParserFunc) Parse(events []interpreter.Event, currentEvent interpr
 	return p(events, currentEvent)
 }
 
+// Parsers are a list of objects that implement the EventsParser interface.
+type Parsers []EventsParser
+
+// Parse runs the parsers in sequence, with each parser receiving the events returned
+// by the parser before it. It stops and returns the error of the first parser that fails.
+func (p Parsers) Parse(events []interpreter.Event, currentEvent interpreter.Event) ([]interpreter.Event, error) {
+	var err error
+	for _, parser := range p {
+		if events, err = parser.Parse(events, currentEvent); err != nil {
+			return []interpreter.Event{}, err
+		}
+	}
+
+	return events, nil
+}
+
 // DefaultCycleParser runs each event in the history through the comparator and returns the entire
 // history if no errors are found.
 func DefaultCycleParser(comparator Comparator) EventsParserFunc {
@@ -64,6 +85,7 @@ func DefaultCycleParser(comparator Comparator) EventsParserFunc {
 			return []interpreter.Event{}, validation.InvalidBootTimeErr{OriginalErr: err}
 		}
 
+		comparator := forHistory(comparator, eventsHistory, currentEvent)
 		var eventList []interpreter.Event
 		for _, event := range eventsHistory {
 			// If comparator returns true, it means we should stop parsing
@@ -91,16 +113,18 @@ func DefaultCycleParser(comparator Comparator) EventsParserFunc {
 // operational event (if available) or the first online event of the current cycle. The returned slice is sorted from
 // newest to oldest primarily by boot-time, and then by birthdate.
 // RebootParser also runs the list of events through the comparator to see if the current event is valid.
-func RebootParser(comparator Comparator) EventsParserFunc {
+// The events marking the start and end of the reboot can be changed with WithRebootDefinition.
+func RebootParser(comparator Comparator, opts ...Option) EventsParserFunc {
 	comparator = setComparator(comparator)
+	o := newOptions(opts)
 	return func(eventsHistory []interpreter.Event, currentEvent interpreter.Event) ([]interpreter.Event, error) {
-		lastCycle, currentCycle, err := parserHelper(eventsHistory, currentEvent, comparator)
+		lastCycle, currentCycle, err := parserHelper(eventsHistory, currentEvent, comparator, o.clusterBootTimes(eventsHistory, currentEvent))
 		if err != nil {
 			return []interpreter.Event{}, err
 		}
 
-		rebootStart := rebootStartParser(lastCycle)
-		rebootEnd := rebootEndParser(currentCycle)
+		rebootStart := o.rebootDefinition.startEvents(lastCycle)
+		rebootEnd := o.rebootDefinition.endEvents(currentCycle)
 		cycle := append(rebootEnd, rebootStart...)
 		return cycle, nil
 	}
@@ -111,15 +135,17 @@ func RebootParser(comparator Comparator) EventsParserFunc {
 // or last offline event and includes all events afterwards that have a birthdate less than or equal to the current event.
 // The returned slice is sorted from newest to oldest primarily by boot-time, and then by birthdate.
 // RebootToCurrentParser also runs the list of events through the comparator to see if the current event is valid.
-func RebootToCurrentParser(comparator Comparator) EventsParserFunc {
+// The events marking the start of the reboot can be changed with WithRebootDefinition.
+func RebootToCurrentParser(comparator Comparator, opts ...Option) EventsParserFunc {
 	comparator = setComparator(comparator)
+	o := newOptions(opts)
 	return func(eventsHistory []interpreter.Event, currentEvent interpreter.Event) ([]interpreter.Event, error) {
-		lastCycle, currentCycle, err := parserHelper(eventsHistory, currentEvent, comparator)
+		lastCycle, currentCycle, err := parserHelper(eventsHistory, currentEvent, comparator, o.clusterBootTimes(eventsHistory, currentEvent))
 		if err != nil {
 			return []interpreter.Event{}, err
 		}
 
-		lastCycle = rebootStartParser(lastCycle)
+		lastCycle = o.rebootDefinition.startEvents(lastCycle)
 		cycle := append(currentCycle, lastCycle...)
 		return cycle, nil
 	}
@@ -128,10 +154,11 @@ func RebootToCurrentParser(comparator Comparator) EventsParserFunc {
 // LastCycleParser returns an EventsParser that takes in a list of events and returns a sorted subset
 // of that list which includes all of the events with the boot-time of the previous cycle sorted from newest to oldest
 // by birthdate. LastCycleParser also runs the list of events through the comparator to see if the current event is valid.
-func LastCycleParser(comparator Comparator) EventsParserFunc {
+func LastCycleParser(comparator Comparator, opts ...Option) EventsParserFunc {
 	comparator = setComparator(comparator)
+	o := newOptions(opts)
 	return func(eventsHistory []interpreter.Event, currentEvent interpreter.Event) ([]interpreter.Event, error) {
-		lastCycle, _, err := parserHelper(eventsHistory, currentEvent, comparator)
+		lastCycle, _, err := parserHelper(eventsHistory, currentEvent, comparator, o.clusterBootTimes(eventsHistory, currentEvent))
 		if err != nil {
 			return []interpreter.Event{}, err
 		}
@@ -144,10 +171,11 @@ func LastCycleParser(comparator Comparator) EventsParserFunc {
 // of that list. The slice includes all of the events with the boot-time of the previous cycle
 // as well as all events with the latest boot-time that have a birthdate less than or equal to the current event.
 // The returned slice is sorted from newest to oldest primarily by boot-time, and then by birthdate.
-func LastCycleToCurrentParser(comparator Comparator) EventsParserFunc {
+func LastCycleToCurrentParser(comparator Comparator, opts ...Option) EventsParserFunc {
 	comparator = setComparator(comparator)
+	o := newOptions(opts)
 	return func(eventsHistory []interpreter.Event, currentEvent interpreter.Event) ([]interpreter.Event, error) {
-		lastCycle, currentCycle, err := parserHelper(eventsHistory, currentEvent, comparator)
+		lastCycle, currentCycle, err := parserHelper(eventsHistory, currentEvent, comparator, o.clusterBootTimes(eventsHistory, currentEvent))
 		if err != nil {
 			return []interpreter.Event{}, err
 		}
@@ -160,10 +188,11 @@ func LastCycleToCurrentParser(comparator Comparator) EventsParserFunc {
 // CurrentCycleParser returns an EventsParser that takes in a list of events and returns a sorted subset
 // of that list which includes all of the events with the boot-time of the current cycle sorted from newest to oldest
 // by birthdate. CurrentCycleParser also runs the list of events through the comparator to see if the current event is valid.
-func CurrentCycleParser(comparator Comparator) EventsParserFunc {
+func CurrentCycleParser(comparator Comparator, opts ...Option) EventsParserFunc {
 	comparator = setComparator(comparator)
+	o := newOptions(opts)
 	return func(eventsHistory []interpreter.Event, currentEvent interpreter.Event) ([]interpreter.Event, error) {
-		currentCycle, err := getSameBootTimeEvents(eventsHistory, currentEvent, comparator)
+		currentCycle, err := getSameBootTimeEvents(eventsHistory, currentEvent, comparator, o.clusterBootTimes(eventsHistory, currentEvent))
 		if err != nil {
 			return []interpreter.Event{}, err
 		}
@@ -176,18 +205,20 @@ func CurrentCycleParser(comparator Comparator) EventsParserFunc {
 // another containing events with the latest boot-time and birthdate less than the currentEvent.
 // It also runs all of the events in the events list through the comparator, and if the comparator returns true,
 // parserHelper will stop and return two empty slices and the error returned by the comparator.
+// Boot-times are compared after being normalized by the clusters passed in.
 // The two slices are sorted from newest to oldest.
-func parserHelper(events []interpreter.Event, currentEvent interpreter.Event, comparator Comparator) ([]interpreter.Event, []interpreter.Event, error) {
-	latestBootTime, err := currentEvent.BootTime()
+func parserHelper(events []interpreter.Event, currentEvent interpreter.Event, comparator Comparator, clusters BootTimeClusters) ([]interpreter.Event, []interpreter.Event, error) {
+	latestBootTime, err := clusters.BootTime(currentEvent)
 	if err != nil || latestBootTime <= 0 {
 		return []interpreter.Event{}, []interpreter.Event{}, validation.InvalidBootTimeErr{OriginalErr: err}
 	}
 
+	comparator = forHistory(comparator, events, currentEvent)
 	var lastCycle []interpreter.Event
 	var currentCycle []interpreter.Event
 	var lastBoottime int64
 	for _, event := range events {
-		bootTime, _ := event.BootTime()
+		bootTime, _ := clusters.BootTime(event)
 		if bootTime <= 0 {
 			continue
 		}
@@ -224,16 +255,18 @@ func parserHelper(events []interpreter.Event, currentEvent interpreter.Event, co
 // getSameBootTimeEvents returns a list of events with the same boot-time as the currentEvent, along with the currentEvent.
 // It also runs all of the events in the events list through the comparator, and if the comparator returns true,
 // getSameBootTimeEvents will stop and return an empty slice and the error returned by the comparator.
+// Boot-times are compared after being normalized by the clusters passed in.
 // The slice is sorted from newest to oldest by birthdate.
-func getSameBootTimeEvents(events []interpreter.Event, currentEvent interpreter.Event, comparator Comparator) ([]interpreter.Event, error) {
-	latestBootTime, err := currentEvent.BootTime()
+func getSameBootTimeEvents(events []interpreter.Event, currentEvent interpreter.Event, comparator Comparator, clusters BootTimeClusters) ([]interpreter.Event, error) {
+	latestBootTime, err := clusters.BootTime(currentEvent)
 	if err != nil || latestBootTime <= 0 {
 		return []interpreter.Event{}, validation.InvalidBootTimeErr{OriginalErr: err}
 	}
 
+	comparator = forHistory(comparator, events, currentEvent)
 	var currentCycle []interpreter.Event
 	for _, event := range events {
-		bootTime, _ := event.BootTime()
+		bootTime, _ := clusters.BootTime(event)
 		if bootTime <= 0 {
 			continue
 		}
@@ -268,67 +301,3 @@ func setComparator(comparator Comparator) Comparator {
 
 	return comparator
 }
-
-// rebootStartParser is a helper function that takes in a list of events
-// and returns a slice containing the last reboot-pending or offline event and any events that come after.
-// If the slice does not contain a reboot-pending or offline event, an empty list is returned.
-// Assumes that all events in the list have the same boot-time.
-func rebootStartParser(events []interpreter.Event) []interpreter.Event {
-	if len(events) == 0 {
-		return events
-	}
-
-	sort.Slice(events, birthdateDescendingSortFunc(events))
-
-	lastOfflineIndex := -1
-	for i, event := range events {
-		eventType, err := event.EventType()
-		if err == nil {
-			if eventType == interpreter.RebootPendingEventType {
-				return events[:i+1]
-			} else if eventType == interpreter.OfflineEventType && lastOfflineIndex == -1 {
-				lastOfflineIndex = i
-			}
-		}
-	}
-
-	return events[:lastOfflineIndex+1]
-}
-
-// rebootEndParser is a helper function that takes in a list of events
-// and returns a slice containing events before the first fully-manageable event. If a fully-manageable event
-// doesn't exist, it looks for the first operational event, then online event. If all these events don't exist, it
-// returns an empty list. Assumes that all events in the list have the same boot-time and that the order of the boot-cycle
-// is: online, operational, fully-manageable.
-func rebootEndParser(events []interpreter.Event) []interpreter.Event {
-	if len(events) == 0 {
-		return events
-	}
-
-	sort.Slice(events, birthdateDescendingSortFunc(events))
-	operationalIndex := -1
-	onlineIndex := -1
-	for i := len(events) - 1; i >= 0; i-- {
-		event := events[i]
-		eventType, err := event.EventType()
-		if err == nil {
-			if eventType == interpreter.FullyManageableEventType {
-				return events[i:]
-			} else if eventType == interpreter.OperationalEventType && operationalIndex == -1 {
-				operationalIndex = i
-			} else if eventType == interpreter.OnlineEventType && onlineIndex == -1 {
-				onlineIndex = i
-			}
-		}
-	}
-
-	if operationalIndex > -1 {
-		return events[operationalIndex:]
-	}
-
-	if onlineIndex > -1 {
-		return events[onlineIndex:]
-	}
-
-	return []interpreter.Event{}
-}
@@ -142,7 +142,7 @@ func (suite *CycleTestSuite) TestParserHelperValid() {
 		boottime, _ := e.BootTime()
 		return (boottime == currentBootTime.Unix() && e.Birthdate <= toEvent.Birthdate) || e.TransactionUUID == toEvent.TransactionUUID
 	})
-	lastCycle, currentCycle, err := parserHelper(suite.Events, toEvent, mockComparator)
+	lastCycle, currentCycle, err := parserHelper(suite.Events, toEvent, mockComparator, BootTimeClusters{})
 	suite.Equal(expectedLastCycle, lastCycle)
 	suite.Equal(expectedCurrentCycle, currentCycle)
 	suite.Nil(err)
@@ -180,7 +180,7 @@ func (suite *CycleTestSuite) TestParserHelperErr() {
 	testErr := errors.New("test")
 	mockComparator.On("Compare", mock.Anything, mock.Anything).Return(true, testErr)
 	toEvent := suite.setEventDestination(fmt.Sprintf("%d-%d", currentBootTime.Unix(), 2), "event-device-status/mac:112233445566/some-event")
-	lastCycle, currentCycle, err := parserHelper(suite.Events, toEvent, mockComparator)
+	lastCycle, currentCycle, err := parserHelper(suite.Events, toEvent, mockComparator, BootTimeClusters{})
 	suite.Empty(lastCycle)
 	suite.Empty(currentCycle)
 	suite.True(errors.Is(err, testErr))
@@ -559,7 +559,7 @@ func TestRebootStartParser(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
 			assert := assert.New(t)
-			cycle := rebootStartParser(tc.events)
+			cycle := DefaultRebootDefinition().startEvents(tc.events)
 			assert.Equal(len(tc.expectedEventIDs), len(cycle))
 			for _, event := range cycle {
 				assert.True(tc.expectedEventIDs[event.TransactionUUID])
@@ -680,7 +680,7 @@ func TestRebootEndParser(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
 			assert := assert.New(t)
-			cycle := rebootEndParser(tc.events)
+			cycle := DefaultRebootDefinition().endEvents(tc.events)
 			assert.Equal(len(tc.expectedEventIDs), len(cycle))
 			for _, event := range cycle {
 				assert.True(tc.expectedEventIDs[event.TransactionUUID])
@@ -784,7 +784,7 @@ func TestGetSameBootTimeEvents(t *testing.T) {
 				}
 			}
 
-			results, err := getSameBootTimeEvents(events, tc.event, tc.comparator)
+			results, err := getSameBootTimeEvents(events, tc.event, tc.comparator, BootTimeClusters{})
 
 			if tc.expectedErr == nil {
 				assert.ElementsMatch(expectedEvents, results)
@@ -893,3 +893,127 @@ func TestSetComparator(t *testing.T) {
 	assert.False(match)
 	assert.Nil(err)
 }
+
+func TestParsersWithTolerance(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	currentEvent := testCycleEvent("5", "fully-manageable", bootTime2+2, now.Add(-30*time.Minute), "b")
+	events := []interpreter.Event{
+		currentEvent,
+		testCycleEvent("4", "operational", bootTime2, now.Add(-40*time.Minute), "b"),
+		testCycleEvent("3", "online", bootTime2+1, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("2", "offline", bootTime1+1, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+	}
+
+	tests := []struct {
+		description      string
+		opts             []Option
+		expectedCurrent  []string
+		expectedLast     []string
+		expectedRebootTo []string
+	}{
+		{
+			description:      "no tolerance",
+			expectedCurrent:  []string{"5"},
+			expectedLast:     []string{"3"},
+			expectedRebootTo: []string{"5"},
+		},
+		{
+			description:      "with tolerance",
+			opts:             []Option{WithBootTimeTolerance(2 * time.Second)},
+			expectedCurrent:  []string{"5", "4", "3"},
+			expectedLast:     []string{"2", "1"},
+			expectedRebootTo: []string{"5", "4", "3", "2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			current, err := CurrentCycleParser(nil, tc.opts...).Parse(events, currentEvent)
+			assert.Nil(err)
+			assert.Equal(tc.expectedCurrent, testEventIDs(current))
+
+			last, err := LastCycleParser(nil, tc.opts...).Parse(events, currentEvent)
+			assert.Nil(err)
+			assert.Equal(tc.expectedLast, testEventIDs(last))
+
+			rebootToCurrent, err := RebootToCurrentParser(nil, tc.opts...).Parse(events, currentEvent)
+			assert.Nil(err)
+			assert.Equal(tc.expectedRebootTo, testEventIDs(rebootToCurrent))
+		})
+	}
+}
+
+func TestParsersInSequence(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-1 * time.Hour).Unix()
+	currentEvent := testCycleEvent("4", "fully-manageable", bootTime, now.Add(-30*time.Minute), "b")
+	events := []interpreter.Event{
+		currentEvent,
+		testCycleEvent("3", "operational", bootTime, now.Add(-40*time.Minute), "b"),
+		testCycleEvent("2", "online", bootTime, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("1", "online", bootTime-100, now.Add(-70*time.Minute), "a"),
+	}
+
+	errParse := errors.New("parse error")
+	failing := EventsParserFunc(func(_ []interpreter.Event, _ interpreter.Event) ([]interpreter.Event, error) {
+		return nil, errParse
+	})
+
+	tests := []struct {
+		description string
+		parsers     Parsers
+		expectedIDs []string
+		expectedErr error
+	}{
+		{
+			description: "no parsers",
+			expectedIDs: []string{"4", "3", "2", "1"},
+		},
+		{
+			description: "cycle then filter",
+			parsers:     Parsers{CurrentCycleParser(nil), FilterParser(EventTypeFilter("online", "operational"))},
+			expectedIDs: []string{"3", "2"},
+		},
+		{
+			description: "filter then cycle",
+			parsers:     Parsers{FilterParser(EventTypeFilter("online")), CurrentCycleParser(nil)},
+			expectedIDs: []string{"4", "2"},
+		},
+		{
+			description: "failing parser",
+			parsers:     Parsers{CurrentCycleParser(nil), failing, FilterParser(EventTypeFilter("online"))},
+			expectedIDs: []string{},
+			expectedErr: errParse,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			parsed, err := tc.parsers.Parse(events, currentEvent)
+			assert.Equal(tc.expectedIDs, testEventIDs(parsed))
+			assert.Equal(tc.expectedErr, err)
+		})
+	}
+}
+
+func TestEventsParserImplementations(t *testing.T) {
+	var parsers []EventsParser
+	parsers = append(parsers,
+		DefaultCycleParser(nil),
+		RebootParser(nil),
+		RebootToCurrentParser(nil),
+		LastCycleParser(nil),
+		LastCycleToCurrentParser(nil),
+		CurrentCycleParser(nil),
+		FilterParser(Filters{}),
+		Parsers{},
+	)
+	assert.Len(t, parsers, 8)
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"strings"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+// Filter decides whether an event should be kept.
+type Filter interface {
+	Keep(event interpreter.Event) bool
+}
+
+// FilterFunc is a function that decides whether an event should be kept.
+type FilterFunc func(interpreter.Event) bool
+
+// Keep runs the FilterFunc, making a FilterFunc a Filter.
+func (f FilterFunc) Keep(event interpreter.Event) bool {
+	return f(event)
+}
+
+// Filters are a list of objects that implement the Filter interface.
+type Filters []Filter
+
+// Keep runs the event through each filter in order and returns false on the
+// first filter that does not keep the event.
+func (f Filters) Keep(event interpreter.Event) bool {
+	for _, filter := range f {
+		if !filter.Keep(event) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterParser returns an EventsParser that returns the events in the history kept by the filter,
+// in the same order as the history. It can be combined with other parsers using Parsers.
+func FilterParser(filter Filter) EventsParserFunc {
+	return func(events []interpreter.Event, _ interpreter.Event) ([]interpreter.Event, error) {
+		filtered := make([]interpreter.Event, 0, len(events))
+		for _, event := range events {
+			if filter.Keep(event) {
+				filtered = append(filtered, event)
+			}
+		}
+
+		return filtered, nil
+	}
+}
+
+// EventTypeFilter returns a Filter that keeps events with one of the event types passed in.
+func EventTypeFilter(eventTypes ...string) FilterFunc {
+	types := make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		types[strings.ToLower(strings.TrimSpace(eventType))] = true
+	}
+
+	return func(event interpreter.Event) bool {
+		eventType, err := event.EventType()
+		return err == nil && types[strings.ToLower(eventType)]
+	}
+}
+
+// ValidatorFilter returns a Filter that keeps events that the validator deems valid.
+func ValidatorFilter(validator validation.Validator) FilterFunc {
+	return func(event interpreter.Event) bool {
+		valid, _ := validator.Valid(event)
+		return valid
+	}
+}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestFilters(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	online := testCycleEvent("1", "online", now.Unix(), now, "a")
+	offline := testCycleEvent("2", "Offline", now.Unix(), now, "a")
+	noType := interpreter.Event{TransactionUUID: "3"}
+	sessionA := FilterFunc(func(event interpreter.Event) bool { return event.SessionID == "a" })
+
+	tests := []struct {
+		description string
+		filter      Filter
+		expected    []bool
+	}{
+		{
+			description: "event type",
+			filter:      EventTypeFilter(" online", "OFFLINE"),
+			expected:    []bool{true, true, false},
+		},
+		{
+			description: "validator",
+			filter:      ValidatorFilter(validation.EventTypeValidator([]string{"online"})),
+			expected:    []bool{true, false, false},
+		},
+		{
+			description: "no filters",
+			filter:      Filters{},
+			expected:    []bool{true, true, true},
+		},
+		{
+			description: "chain",
+			filter:      Filters{sessionA, EventTypeFilter("offline")},
+			expected:    []bool{false, true, false},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			for i, event := range []interpreter.Event{online, offline, noType} {
+				assert.Equal(tc.expected[i], tc.filter.Keep(event), event.TransactionUUID)
+			}
+		})
+	}
+}
+
+func TestFilterParser(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", now.Unix(), now, "a"),
+		testCycleEvent("2", "offline", now.Unix(), now, "a"),
+		testCycleEvent("3", "online", now.Unix(), now, "b"),
+	}
+
+	assert := assert.New(t)
+	parsed, err := FilterParser(EventTypeFilter("online")).Parse(events, events[0])
+	assert.Nil(err)
+	assert.Equal([]string{"1", "3"}, testEventIDs(parsed))
+
+	parsed, err = FilterParser(EventTypeFilter("fully-manageable")).Parse(events, events[0])
+	assert.Nil(err)
+	assert.Empty(parsed)
+}
@@ -0,0 +1,401 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+const (
+	// FirmwareNameKey and ModelKey are the metadata keys that devices report their firmware name and model under.
+	FirmwareNameKey = "fw-name"
+	ModelKey        = "hw-model"
+
+	// Names of the subexpressions that firmware patterns use to capture the components of a version.
+	MajorSubexpName = "major"
+	MinorSubexpName = "minor"
+	PatchSubexpName = "patch"
+)
+
+var (
+	ErrFirmwareVersionParse   = errors.New("unable to parse firmware version")
+	ErrInvalidFirmwarePattern = errors.New("invalid firmware pattern")
+	ErrFirmwareDowngrade      = errors.New("firmware was downgraded")
+	ErrSkippedFirmware        = errors.New("mandatory firmware version was skipped")
+	ErrFirmwareOutOfRange     = errors.New("firmware version is outside of the allowed range")
+
+	// DefaultFirmwareRegex is used for models without their own pattern. It finds the first version made
+	// of two or three numbers separated by dots, such as 4.2 or 4.2.1.
+	DefaultFirmwareRegex = regexp.MustCompile(fmt.Sprintf(`(?P<%s>\d+)\.(?P<%s>\d+)(?:\.(?P<%s>\d+))?`, MajorSubexpName, MinorSubexpName, PatchSubexpName))
+)
+
+// FirmwareVersion is the semver-like version of a firmware.
+type FirmwareVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseFirmwareVersion parses a plain version made of up to three numbers separated by dots, such as 4, 4.2 or 4.2.1.
+// Missing components are 0.
+func ParseFirmwareVersion(version string) (FirmwareVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) > 3 {
+		return FirmwareVersion{}, fmt.Errorf("%w: %s", ErrFirmwareVersionParse, version)
+	}
+
+	components := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return FirmwareVersion{}, fmt.Errorf("%w: %s", ErrFirmwareVersionParse, version)
+		}
+		components[i] = n
+	}
+
+	return FirmwareVersion{Major: components[0], Minor: components[1], Patch: components[2]}, nil
+}
+
+// Compare returns -1 if v is older than other, 1 if v is newer than other and 0 if they are the same version.
+func (v FirmwareVersion) Compare(other FirmwareVersion) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (v FirmwareVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// FirmwarePattern is a regex that extracts the version from the firmware names of a model. The pattern must have a
+// subexpression named major, and can have subexpressions named minor and patch. Without named subexpressions, the
+// first three subexpressions are used as the major, minor and patch components.
+type FirmwarePattern struct {
+	Model   string
+	Pattern string
+}
+
+// FirmwareParser extracts versions from firmware names, using the pattern of the device's model.
+type FirmwareParser struct {
+	patterns map[string]*regexp.Regexp
+}
+
+// NewFirmwareParser compiles the patterns into a FirmwareParser. Models are matched ignoring case,
+// and DefaultFirmwareRegex is used for models without a pattern. ErrInvalidFirmwarePattern is returned
+// for patterns without subexpressions, or with named subexpressions but none named major.
+func NewFirmwareParser(patterns []FirmwarePattern) (FirmwareParser, error) {
+	parser := FirmwareParser{patterns: make(map[string]*regexp.Regexp, len(patterns))}
+	for _, p := range patterns {
+		regex, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return FirmwareParser{}, fmt.Errorf("%w for model %s: %v", ErrInvalidFirmwarePattern, p.Model, err)
+		}
+
+		if regex.NumSubexp() == 0 {
+			return FirmwareParser{}, fmt.Errorf("%w for model %s: no subexpressions", ErrInvalidFirmwarePattern, p.Model)
+		}
+
+		if regex.SubexpIndex(MajorSubexpName) < 0 {
+			for _, name := range regex.SubexpNames() {
+				if len(name) > 0 {
+					return FirmwareParser{}, fmt.Errorf("%w for model %s: named subexpressions without %s", ErrInvalidFirmwarePattern, p.Model, MajorSubexpName)
+				}
+			}
+		}
+
+		parser.patterns[strings.ToLower(p.Model)] = regex
+	}
+
+	return parser, nil
+}
+
+// Parse returns the version of a firmware name reported by a device of the model.
+func (p FirmwareParser) Parse(firmwareName string, model string) (FirmwareVersion, error) {
+	regex, found := p.patterns[strings.ToLower(model)]
+	if !found {
+		regex = DefaultFirmwareRegex
+	}
+
+	match := regex.FindStringSubmatch(firmwareName)
+	if match == nil {
+		return FirmwareVersion{}, fmt.Errorf("%w: %s", ErrFirmwareVersionParse, firmwareName)
+	}
+
+	indexes := []int{regex.SubexpIndex(MajorSubexpName), regex.SubexpIndex(MinorSubexpName), regex.SubexpIndex(PatchSubexpName)}
+	if indexes[0] < 0 {
+		indexes = []int{1, 2, 3}
+	}
+
+	components := make([]int, 3)
+	for i, index := range indexes {
+		if index < 0 || index >= len(match) || len(match[index]) == 0 {
+			continue
+		}
+
+		n, err := strconv.Atoi(match[index])
+		if err != nil {
+			return FirmwareVersion{}, fmt.Errorf("%w: %s", ErrFirmwareVersionParse, firmwareName)
+		}
+		components[i] = n
+	}
+
+	return FirmwareVersion{Major: components[0], Minor: components[1], Patch: components[2]}, nil
+}
+
+// FirmwareRule is which firmware versions a model is allowed to run. Versions are plain versions
+// parsed with ParseFirmwareVersion, and empty versions are not checked.
+type FirmwareRule struct {
+	// Model is the model the rule applies to, ignoring case. A rule without a model applies to every model.
+	Model string
+
+	// Min and Max are the oldest and newest versions the model is allowed to run, inclusive.
+	Min string
+	Max string
+
+	// Mandatory are the versions the model must run when upgrading past them.
+	Mandatory []string
+}
+
+// firmwareCycle is the firmware a device ran during a boot cycle.
+type firmwareCycle struct {
+	BootTime int64
+	Model    string
+	Name     string
+	Version  FirmwareVersion
+
+	// Event is the oldest event in the cycle with a firmware name, which is where Name came from.
+	Event interpreter.Event
+}
+
+func (c firmwareCycle) String() string {
+	return fmt.Sprintf("%s (%s) at boot-time %d", c.Name, c.Version, c.BootTime)
+}
+
+// firmwareCycles returns the firmware of each boot cycle, sorted from oldest to newest by boot-time. The firmware name
+// and model of a cycle are those of the oldest event in the cycle that has them, and cycles whose firmware version
+// cannot be parsed are left out.
+func firmwareCycles(events []interpreter.Event, parser FirmwareParser, opts []Option) []firmwareCycle {
+	cycles := SplitIntoCycles(events, opts...)
+	var firmware []firmwareCycle
+	for i := len(cycles) - 1; i >= 0; i-- {
+		cycle := firmwareCycle{BootTime: cycles[i].BootTime}
+		for _, event := range cycles[i].events {
+			if name, found := event.GetMetadataValue(FirmwareNameKey); found && len(cycle.Name) == 0 {
+				cycle.Name = name
+				cycle.Event = event
+			}
+			if model, found := event.GetMetadataValue(ModelKey); found && len(cycle.Model) == 0 {
+				cycle.Model = model
+			}
+		}
+
+		version, err := parser.Parse(cycle.Name, cycle.Model)
+		if len(cycle.Name) == 0 || err != nil {
+			continue
+		}
+
+		cycle.Version = version
+		firmware = append(firmware, cycle)
+	}
+
+	return firmware
+}
+
+// FirmwareValidators returns the validators that check the firmware a device runs across its history:
+// FirmwareDowngradeValidator, MandatoryFirmwareValidator and FirmwareRangeValidator.
+func FirmwareValidators(parser FirmwareParser, rules []FirmwareRule, opts ...Option) (CycleValidators, error) {
+	mandatory, err := MandatoryFirmwareValidator(parser, rules, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	firmwareRange, err := FirmwareRangeValidator(parser, rules, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return CycleValidators{
+		FirmwareDowngradeValidator(parser, opts...),
+		mandatory,
+		firmwareRange,
+	}, nil
+}
+
+// FirmwareDowngradeValidator returns a CycleValidatorFunc that validates that the firmware version of each boot cycle is
+// not older than the version of the boot cycle before it. Versions are parsed from the fw-name metadata of each cycle with
+// the parser, and cycles whose version cannot be parsed are skipped. It is meant to be run on a device's whole history.
+func FirmwareDowngradeValidator(parser FirmwareParser, opts ...Option) CycleValidatorFunc {
+	return func(events []interpreter.Event) (bool, error) {
+		var details []string
+		var offending []interpreter.Event
+		cycles := firmwareCycles(events, parser, opts)
+		for i := 1; i < len(cycles); i++ {
+			if cycles[i].Version.Compare(cycles[i-1].Version) < 0 {
+				details = append(details, fmt.Sprintf("%s -> %s", cycles[i-1], cycles[i]))
+				offending = append(offending, cycles[i-1].Event, cycles[i].Event)
+			}
+		}
+
+		if len(details) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrFirmwareDowngrade,
+			ErrorDetailKey:    "firmware downgrades",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.FirmwareDowngrade,
+			OffendingEvents:   uniqueEvents(offending),
+		}
+	}
+}
+
+// MandatoryFirmwareValidator returns a CycleValidatorFunc that validates that a device never upgrades past a mandatory
+// version of its model's rules without running it, meaning that no boot cycle runs a version newer than a mandatory
+// version when the boot cycle before it runs an older one. Versions are parsed the same way as FirmwareDowngradeValidator.
+// An error is returned if a mandatory version cannot be parsed. It is meant to be run on a device's whole history.
+func MandatoryFirmwareValidator(parser FirmwareParser, rules []FirmwareRule, opts ...Option) (CycleValidatorFunc, error) {
+	mandatory := make(map[string][]FirmwareVersion, len(rules))
+	for _, rule := range rules {
+		for _, m := range rule.Mandatory {
+			version, err := ParseFirmwareVersion(m)
+			if err != nil {
+				return nil, err
+			}
+
+			model := strings.ToLower(rule.Model)
+			mandatory[model] = append(mandatory[model], version)
+		}
+	}
+
+	return func(events []interpreter.Event) (bool, error) {
+		var details []string
+		var offending []interpreter.Event
+		cycles := firmwareCycles(events, parser, opts)
+		for i := 1; i < len(cycles); i++ {
+			previous, current := cycles[i-1], cycles[i]
+			var versions []FirmwareVersion
+			versions = append(versions, mandatory[""]...)
+			versions = append(versions, mandatory[strings.ToLower(current.Model)]...)
+			for _, version := range versions {
+				if previous.Version.Compare(version) < 0 && current.Version.Compare(version) > 0 {
+					details = append(details, fmt.Sprintf("%s skipped from %s -> %s", version, previous, current))
+					offending = append(offending, previous.Event, current.Event)
+				}
+			}
+		}
+
+		if len(details) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrSkippedFirmware,
+			ErrorDetailKey:    "skipped firmware versions",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.SkippedFirmwareVersion,
+			OffendingEvents:   uniqueEvents(offending),
+		}
+	}, nil
+}
+
+// FirmwareRangeValidator returns a CycleValidatorFunc that validates that every boot cycle runs a firmware version within
+// the minimum and maximum versions of its model's rules, where the model is parsed from the hw-model metadata. Versions
+// are parsed the same way as FirmwareDowngradeValidator. An error is returned if a minimum or maximum version cannot be
+// parsed. It is meant to be run on a device's whole history.
+func FirmwareRangeValidator(parser FirmwareParser, rules []FirmwareRule, opts ...Option) (CycleValidatorFunc, error) {
+	type versionRange struct {
+		model    string
+		min, max *FirmwareVersion
+	}
+
+	ranges := make([]versionRange, 0, len(rules))
+	for _, rule := range rules {
+		min, err := parseFirmwareBound(rule.Min)
+		if err != nil {
+			return nil, err
+		}
+
+		max, err := parseFirmwareBound(rule.Max)
+		if err != nil {
+			return nil, err
+		}
+
+		ranges = append(ranges, versionRange{model: rule.Model, min: min, max: max})
+	}
+
+	return func(events []interpreter.Event) (bool, error) {
+		var details []string
+		var offending []interpreter.Event
+		for _, cycle := range firmwareCycles(events, parser, opts) {
+			for _, r := range ranges {
+				if len(r.model) > 0 && !strings.EqualFold(r.model, cycle.Model) {
+					continue
+				}
+
+				if r.min != nil && cycle.Version.Compare(*r.min) < 0 {
+					details = append(details, fmt.Sprintf("%s on %s below minimum %s", cycle, cycle.Model, r.min))
+					offending = append(offending, cycle.Event)
+				} else if r.max != nil && cycle.Version.Compare(*r.max) > 0 {
+					details = append(details, fmt.Sprintf("%s on %s above maximum %s", cycle, cycle.Model, r.max))
+					offending = append(offending, cycle.Event)
+				}
+			}
+		}
+
+		if len(details) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrFirmwareOutOfRange,
+			ErrorDetailKey:    "firmware out of range",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.FirmwareOutOfRange,
+			OffendingEvents:   uniqueEvents(offending),
+		}
+	}, nil
+}
+
+// parseFirmwareBound parses a minimum or maximum version, returning nil if the version is empty.
+func parseFirmwareBound(version string) (*FirmwareVersion, error) {
+	if len(version) == 0 {
+		return nil, nil
+	}
+
+	v, err := ParseFirmwareVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
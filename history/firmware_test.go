@@ -0,0 +1,178 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestFirmwareParser(t *testing.T) {
+	parser, err := NewFirmwareParser([]FirmwarePattern{
+		{Model: "TG1682", Pattern: `_(?P<major>\d+)\.(?P<minor>\d+)p(?P<patch>\d+)`},
+		{Model: "model-1", Pattern: `fw-(\d+)`},
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		description     string
+		firmwareName    string
+		model           string
+		expectedVersion FirmwareVersion
+		expectedErr     error
+	}{
+		{
+			description:     "named subexpressions",
+			firmwareName:    "TG1682_3.14p9s6_PROD_sey",
+			model:           "tg1682",
+			expectedVersion: FirmwareVersion{Major: 3, Minor: 14, Patch: 9},
+		},
+		{
+			description:     "unnamed subexpressions",
+			firmwareName:    "fw-3",
+			model:           "model-1",
+			expectedVersion: FirmwareVersion{Major: 3},
+		},
+		{
+			description:     "default pattern",
+			firmwareName:    "device_4.2.1_prod",
+			model:           "other",
+			expectedVersion: FirmwareVersion{Major: 4, Minor: 2, Patch: 1},
+		},
+		{
+			description:     "default pattern without patch",
+			firmwareName:    "device_4.2_prod",
+			expectedVersion: FirmwareVersion{Major: 4, Minor: 2},
+		},
+		{
+			description:  "no match",
+			firmwareName: "fw-",
+			model:        "model-1",
+			expectedErr:  ErrFirmwareVersionParse,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			version, err := parser.Parse(tc.firmwareName, tc.model)
+			assert.True(errors.Is(err, tc.expectedErr))
+			assert.Equal(tc.expectedVersion, version)
+		})
+	}
+
+	_, err = NewFirmwareParser([]FirmwarePattern{{Model: "bad", Pattern: `(`}})
+	assert.True(t, errors.Is(err, ErrInvalidFirmwarePattern))
+	_, err = NewFirmwareParser([]FirmwarePattern{{Model: "bad", Pattern: `fw-\d+`}})
+	assert.True(t, errors.Is(err, ErrInvalidFirmwarePattern))
+	_, err = NewFirmwareParser([]FirmwarePattern{{Model: "bad", Pattern: `fw-(?P<minor>\d+)\.(\d+)`}})
+	assert.True(t, errors.Is(err, ErrInvalidFirmwarePattern))
+}
+
+func TestParseFirmwareVersion(t *testing.T) {
+	assert := assert.New(t)
+	version, err := ParseFirmwareVersion("v4.2")
+	assert.Nil(err)
+	assert.Equal(FirmwareVersion{Major: 4, Minor: 2}, version)
+	assert.Equal("4.2.0", version.String())
+	assert.Equal(0, version.Compare(FirmwareVersion{Major: 4, Minor: 2}))
+	assert.Equal(-1, version.Compare(FirmwareVersion{Major: 4, Minor: 2, Patch: 1}))
+	assert.Equal(1, version.Compare(FirmwareVersion{Major: 3, Minor: 9, Patch: 9}))
+
+	for _, invalid := range []string{"", "4.x", "1.2.3.4", "-1"} {
+		_, err = ParseFirmwareVersion(invalid)
+		assert.True(errors.Is(err, ErrFirmwareVersionParse), invalid)
+	}
+}
+
+func TestFirmwareValidators(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+
+	// one boot cycle per firmware name, an hour apart
+	firmwareEvents := func(model string, names ...string) []interpreter.Event {
+		var events []interpreter.Event
+		for i, name := range names {
+			bootTime := now.Add(time.Duration(i-len(names)) * time.Hour)
+			event := testCycleEvent(name, "online", bootTime.Unix(), bootTime.Add(time.Minute), name)
+			event.Metadata[FirmwareNameKey] = name
+			event.Metadata[ModelKey] = model
+			events = append(events, event)
+		}
+		return events
+	}
+
+	rules := []FirmwareRule{
+		{Mandatory: []string{"2.0"}},
+		{Model: "model-1", Min: "1.1", Max: "3.0", Mandatory: []string{"2.5"}},
+	}
+
+	tests := []struct {
+		description     string
+		events          []interpreter.Event
+		expectedTags    []validation.Tag
+		expectedDetails []string
+	}{
+		{
+			description: "valid",
+			events:      firmwareEvents("model-1", "fw_1.1", "fw_2.0", "fw_2.5", "fw_2.5", "fw_3.0"),
+		},
+		{
+			description:     "downgrade",
+			events:          firmwareEvents("model-1", "fw_1.1", "fw_2.0", "fw_1.2", "unknown"),
+			expectedTags:    []validation.Tag{validation.FirmwareDowngrade},
+			expectedDetails: []string{fmt.Sprintf("fw_2.0 (2.0.0) at boot-time %d -> fw_1.2 (1.2.0) at boot-time %d", now.Add(-3*time.Hour).Unix(), now.Add(-2*time.Hour).Unix())},
+		},
+		{
+			description:  "skipped mandatory",
+			events:       firmwareEvents("model-1", "fw_1.1", "fw_2.6"),
+			expectedTags: []validation.Tag{validation.SkippedFirmwareVersion},
+			expectedDetails: []string{
+				fmt.Sprintf("2.0.0 skipped from fw_1.1 (1.1.0) at boot-time %d -> fw_2.6 (2.6.0) at boot-time %d", now.Add(-2*time.Hour).Unix(), now.Add(-time.Hour).Unix()),
+				fmt.Sprintf("2.5.0 skipped from fw_1.1 (1.1.0) at boot-time %d -> fw_2.6 (2.6.0) at boot-time %d", now.Add(-2*time.Hour).Unix(), now.Add(-time.Hour).Unix()),
+			},
+		},
+		{
+			description:  "out of range",
+			events:       firmwareEvents("MODEL-1", "fw_1.0", "fw_3.1"),
+			expectedTags: []validation.Tag{validation.SkippedFirmwareVersion, validation.FirmwareOutOfRange},
+		},
+		{
+			description: "other model",
+			events:      firmwareEvents("model-2", "fw_1.0", "fw_2.0", "fw_3.1"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			validators, err := FirmwareValidators(FirmwareParser{}, rules)
+			assert.Nil(err)
+			valid, err := validators.Valid(tc.events)
+			if len(tc.expectedTags) == 0 {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			var errs validation.Errors
+			assert.True(errors.As(err, &errs))
+			assert.Equal(tc.expectedTags, errs.Tags())
+			if len(tc.expectedDetails) > 0 {
+				var cvErr CycleValidationErr
+				assert.True(errors.As(errs[0], &cvErr))
+				assert.Equal(tc.expectedDetails, cvErr.Fields())
+			}
+		})
+	}
+
+	_, err = FirmwareValidators(FirmwareParser{}, []FirmwareRule{{Mandatory: []string{"x"}}})
+	assert.True(t, errors.Is(err, ErrFirmwareVersionParse))
+	_, err = FirmwareValidators(FirmwareParser{}, []FirmwareRule{{Max: "x"}})
+	assert.True(t, errors.Is(err, ErrFirmwareVersionParse))
+}
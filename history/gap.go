@@ -0,0 +1,185 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrEventGap = errors.New("gap between events")
+)
+
+// GapLimit is the longest time allowed from an event of type From to the next event of type To,
+// or to the last event if there is no event of type To after it. Event types are case-insensitive.
+type GapLimit struct {
+	From   string
+	To     string
+	MaxGap time.Duration
+}
+
+// Gap is a period of time between two events that is longer than allowed.
+type Gap struct {
+	Start    interpreter.Event
+	End      interpreter.Event
+	Duration time.Duration
+
+	// MaxGap is the limit that the gap exceeded.
+	MaxGap time.Duration
+
+	// Missing is the To event type of the limit if no event of that type followed Start,
+	// in which case the gap runs from Start to the last event.
+	Missing string
+}
+
+// String returns the event types and transaction uuids of the gap's events along with its duration.
+func (g Gap) String() string {
+	startType, _ := g.Start.EventType()
+	endType, _ := g.End.EventType()
+	s := fmt.Sprintf("%s (%s) -> %s (%s): %s, max %s", startType, g.Start.TransactionUUID, endType, g.End.TransactionUUID, g.Duration, g.MaxGap)
+	if len(g.Missing) > 0 {
+		s = fmt.Sprintf("%s, no %s", s, g.Missing)
+	}
+
+	return s
+}
+
+// GapErr is returned by GapValidator and contains every gap found.
+type GapErr struct {
+	Gaps []Gap
+}
+
+func (e GapErr) Error() string {
+	return fmt.Sprintf("%v: %d found", ErrEventGap, len(e.Gaps))
+}
+
+func (e GapErr) Unwrap() error {
+	return ErrEventGap
+}
+
+// Tag implements the TaggedError interface.
+func (e GapErr) Tag() validation.Tag {
+	return validation.EventGap
+}
+
+// GapValidator returns a CycleValidatorFunc that walks the events from oldest to newest by birthdate and
+// validates that no two consecutive events are more than maxGap apart, and that, for each limit, no event of
+// type To comes more than the limit's MaxGap after the event of type From before it. An event of type From that is
+// not followed by an event of type To is reported if the last event comes more than the limit's MaxGap after it.
+// A maxGap of 0 does not limit the time between consecutive events. Because the validator only looks at the events
+// it is given, it is meant to be run on a single boot cycle.
+func GapValidator(maxGap time.Duration, limits []GapLimit) CycleValidatorFunc {
+	return func(events []interpreter.Event) (bool, error) {
+		eventsCopy := make([]interpreter.Event, len(events))
+		copy(eventsCopy, events)
+		sort.SliceStable(eventsCopy, func(a, b int) bool {
+			return eventsCopy[a].Birthdate < eventsCopy[b].Birthdate
+		})
+
+		var gaps []Gap
+		if maxGap > 0 {
+			for i := 1; i < len(eventsCopy); i++ {
+				if gap := newGap(eventsCopy[i-1], eventsCopy[i], maxGap); gap != nil {
+					gaps = append(gaps, *gap)
+				}
+			}
+		}
+
+		for _, limit := range limits {
+			gaps = append(gaps, findPairGaps(eventsCopy, limit)...)
+		}
+
+		if len(gaps) == 0 {
+			return true, nil
+		}
+
+		details := make([]string, 0, len(gaps))
+		offending := make([]interpreter.Event, 0, 2*len(gaps))
+		for _, gap := range gaps {
+			details = append(details, gap.String())
+			offending = append(offending, gap.Start, gap.End)
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       GapErr{Gaps: gaps},
+			ErrorDetailKey:    "gaps",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.EventGap,
+			OffendingEvents:   uniqueEvents(offending),
+		}
+	}
+}
+
+// find the gaps between each event of the limit's From type and the first event of the
+// limit's To type that follows it, in a list of events sorted by birthdate.
+func findPairGaps(events []interpreter.Event, limit GapLimit) []Gap {
+	if limit.MaxGap <= 0 {
+		return nil
+	}
+
+	var gaps []Gap
+	var start *interpreter.Event
+	for i := range events {
+		eventType, err := events[i].EventType()
+		if err != nil {
+			continue
+		}
+
+		if start != nil && strings.EqualFold(eventType, limit.To) {
+			if gap := newGap(*start, events[i], limit.MaxGap); gap != nil {
+				gaps = append(gaps, *gap)
+			}
+			start = nil
+		}
+
+		if strings.EqualFold(eventType, limit.From) {
+			start = &events[i]
+		}
+	}
+
+	// a From event that is never followed by a To event is measured up to the last event
+	if start != nil {
+		if gap := newGap(*start, events[len(events)-1], limit.MaxGap); gap != nil {
+			gap.Missing = limit.To
+			gaps = append(gaps, *gap)
+		}
+	}
+
+	return gaps
+}
+
+func newGap(start interpreter.Event, end interpreter.Event, maxGap time.Duration) *Gap {
+	duration := time.Duration(end.Birthdate - start.Birthdate)
+	if duration <= maxGap {
+		return nil
+	}
+
+	return &Gap{
+		Start:    start,
+		End:      end,
+		Duration: duration,
+		MaxGap:   maxGap,
+	}
+}
@@ -0,0 +1,150 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestGapValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("4", "offline", bootTime, now.Add(-10*time.Minute), "a"),
+		testCycleEvent("3", "fully-manageable", bootTime, now.Add(-80*time.Minute), "a"),
+		testCycleEvent("2", "operational", bootTime, now.Add(-90*time.Minute), "a"),
+		testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+	}
+
+	tests := []struct {
+		description       string
+		maxGap            time.Duration
+		limits            []GapLimit
+		events            []interpreter.Event
+		expectedGaps      [][]string
+		expectedDurations []time.Duration
+	}{
+		{
+			description: "no limits",
+			events:      events,
+		},
+		{
+			description: "empty",
+			maxGap:      time.Minute,
+			limits:      []GapLimit{{From: "online", To: "operational", MaxGap: time.Minute}},
+		},
+		{
+			description: "within limits",
+			maxGap:      time.Hour + 10*time.Minute,
+			limits:      []GapLimit{{From: "online", To: "operational", MaxGap: 20 * time.Minute}},
+			events:      events,
+		},
+		{
+			description:       "global gap",
+			maxGap:            time.Hour,
+			events:            events,
+			expectedGaps:      [][]string{{"3", "4"}},
+			expectedDurations: []time.Duration{70 * time.Minute},
+		},
+		{
+			description:       "multiple global gaps",
+			maxGap:            15 * time.Minute,
+			events:            events,
+			expectedGaps:      [][]string{{"1", "2"}, {"3", "4"}},
+			expectedDurations: []time.Duration{20 * time.Minute, 70 * time.Minute},
+		},
+		{
+			description:       "pair gap",
+			limits:            []GapLimit{{From: "ONLINE", To: "fully-manageable", MaxGap: 20 * time.Minute}},
+			events:            events,
+			expectedGaps:      [][]string{{"1", "3"}},
+			expectedDurations: []time.Duration{30 * time.Minute},
+		},
+		{
+			description: "pair not found",
+			limits:      []GapLimit{{From: "offline", To: "online", MaxGap: time.Minute}},
+			events:      events,
+		},
+		{
+			description:       "pair never ended",
+			limits:            []GapLimit{{From: "online", To: "operational", MaxGap: 5 * time.Minute}},
+			events:            []interpreter.Event{events[0], events[1], events[3]},
+			expectedGaps:      [][]string{{"1", "4"}},
+			expectedDurations: []time.Duration{100 * time.Minute},
+		},
+		{
+			description: "pair never ended within limit",
+			limits:      []GapLimit{{From: "online", To: "operational", MaxGap: 5 * time.Minute}},
+			events: []interpreter.Event{
+				testCycleEvent("2", "fully-manageable", bootTime, now.Add(-107*time.Minute), "a"),
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+			},
+		},
+		{
+			description: "pair gap from latest from event",
+			limits:      []GapLimit{{From: "online", To: "operational", MaxGap: 5 * time.Minute}},
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-93*time.Minute), "b"),
+				testCycleEvent("3", "operational", bootTime, now.Add(-90*time.Minute), "b"),
+			},
+		},
+		{
+			description:       "global and pair gaps",
+			maxGap:            time.Hour,
+			limits:            []GapLimit{{From: "online", To: "operational", MaxGap: 10 * time.Minute}},
+			events:            events,
+			expectedGaps:      [][]string{{"3", "4"}, {"1", "2"}},
+			expectedDurations: []time.Duration{70 * time.Minute, 20 * time.Minute},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			valid, err := GapValidator(tc.maxGap, tc.limits).Valid(tc.events)
+			if len(tc.expectedGaps) == 0 {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			var cvErr CycleValidationErr
+			assert.True(errors.As(err, &cvErr))
+			assert.Equal(validation.EventGap, cvErr.Tag())
+			assert.Len(cvErr.Fields(), len(tc.expectedGaps))
+
+			var gapErr GapErr
+			assert.True(errors.As(err, &gapErr))
+			assert.True(errors.Is(err, ErrEventGap))
+			var gaps [][]string
+			var durations []time.Duration
+			for _, gap := range gapErr.Gaps {
+				gaps = append(gaps, []string{gap.Start.TransactionUUID, gap.End.TransactionUUID})
+				durations = append(durations, gap.Duration)
+			}
+			assert.Equal(tc.expectedGaps, gaps)
+			assert.Equal(tc.expectedDurations, durations)
+		})
+	}
+}
+
+func TestGapString(t *testing.T) {
+	now := time.Now()
+	gap := Gap{
+		Start:    testCycleEvent("1", "online", now.Unix(), now, "a"),
+		End:      testCycleEvent("2", "operational", now.Unix(), now.Add(time.Hour), "a"),
+		Duration: time.Hour,
+		MaxGap:   time.Minute,
+	}
+	assert.Equal(t, "online (1) -> operational (2): 1h0m0s, max 1m0s", gap.String())
+
+	gap.Missing = "fully-manageable"
+	assert.Equal(t, "online (1) -> operational (2): 1h0m0s, max 1m0s, no fully-manageable", gap.String())
+}
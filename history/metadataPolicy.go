@@ -0,0 +1,192 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrIllegalMetadataChange        = errors.New("illegal metadata change")
+	ErrMetadataChangeOutOfScope     = fmt.Errorf("%w: value changed outside of its allowed scope", ErrIllegalMetadataChange)
+	ErrMetadataTransitionNotAllowed = fmt.Errorf("%w: value changed to a value it is not allowed to change to", ErrIllegalMetadataChange)
+	ErrInvalidChangeScope           = errors.New("invalid metadata change scope")
+)
+
+// ChangeScope is when the value of a metadata key is allowed to change.
+type ChangeScope string
+
+const (
+	// ChangeNever does not allow the value to change, such as for hw-serial-number or hw-mac.
+	ChangeNever ChangeScope = "never"
+
+	// ChangePerBoot only allows the value to change across a reboot, such as for fw-name.
+	ChangePerBoot ChangeScope = "per-boot"
+
+	// ChangePerSession only allows the value to change between sessions or across a reboot, such as for webpa-protocol.
+	ChangePerSession ChangeScope = "per-session"
+
+	// ChangeAny allows the value to change at any time. This is the default.
+	ChangeAny ChangeScope = "any"
+)
+
+// ParseChangeScope returns the ChangeScope with the name, ignoring case. An empty name is ChangeAny.
+func ParseChangeScope(name string) (ChangeScope, error) {
+	switch scope := ChangeScope(strings.ToLower(strings.TrimSpace(name))); scope {
+	case "":
+		return ChangeAny, nil
+	case ChangeNever, ChangePerBoot, ChangePerSession, ChangeAny:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidChangeScope, name)
+	}
+}
+
+// allows returns whether the scope allows the change. Unknown scopes allow nothing.
+func (s ChangeScope) allows(change MetadataChange) bool {
+	scope, err := ParseChangeScope(string(s))
+	if err != nil {
+		return false
+	}
+
+	switch scope {
+	case ChangeAny:
+		return true
+	case ChangePerSession:
+		return change.AcrossSession || change.AcrossReboot
+	case ChangePerBoot:
+		return change.AcrossReboot
+	default:
+		return false
+	}
+}
+
+// AnyMetadataValue matches every value in a MetadataTransition.
+const AnyMetadataValue = "*"
+
+// MetadataTransition is an allowed change from one value of a metadata key to another.
+// Either value can be AnyMetadataValue.
+type MetadataTransition struct {
+	From string
+	To   string
+}
+
+func (t MetadataTransition) matches(change MetadataChange) bool {
+	return (t.From == AnyMetadataValue || t.From == change.From) && (t.To == AnyMetadataValue || t.To == change.To)
+}
+
+// MetadataPolicy is how the value of a metadata key is allowed to change.
+type MetadataPolicy struct {
+	Key   string
+	Scope ChangeScope
+
+	// Transitions are the changes allowed within the scope. If it is empty, every change within the scope is allowed.
+	Transitions []MetadataTransition
+}
+
+// MetadataViolation is a metadata change that is not allowed by the key's policy.
+type MetadataViolation struct {
+	Change MetadataChange
+
+	// Err is ErrMetadataChangeOutOfScope or ErrMetadataTransitionNotAllowed.
+	Err error
+}
+
+// MetadataPolicyErr is returned by MetadataPolicyValidator and contains every metadata change that is not allowed.
+type MetadataPolicyErr struct {
+	Violations []MetadataViolation
+}
+
+func (e MetadataPolicyErr) Error() string {
+	return fmt.Sprintf("%v: %d changes found", ErrIllegalMetadataChange, len(e.Violations))
+}
+
+func (e MetadataPolicyErr) Unwrap() error {
+	return ErrIllegalMetadataChange
+}
+
+// Tag implements the TaggedError interface.
+func (e MetadataPolicyErr) Tag() validation.Tag {
+	return validation.IllegalMetadataChange
+}
+
+// MetadataPolicyValidator returns a CycleValidatorFunc that validates that the values of metadata keys only change the way
+// their policies allow. The changes are found with NewMetadataTimeline, and each change must be within the scope of its key's
+// policy and, if the policy lists transitions, match one of them. Unlike MetadataValidator, which only reports the keys that
+// are inconsistent, every illegal change is reported along with the event it happened in. It is meant to be run on a device's
+// whole history.
+func MetadataPolicyValidator(policies []MetadataPolicy, opts ...Option) CycleValidatorFunc {
+	keys := make([]string, 0, len(policies))
+	byKey := make(map[string]MetadataPolicy, len(policies))
+	for _, policy := range policies {
+		keys = append(keys, policy.Key)
+		byKey[policy.Key] = policy
+	}
+
+	return func(events []interpreter.Event) (bool, error) {
+		var violations []MetadataViolation
+		var details []string
+		var offending []interpreter.Event
+		for _, change := range NewMetadataTimeline(events, keys, opts...).Changes {
+			policy := byKey[change.Key]
+			var err error
+			if !policy.Scope.allows(change) {
+				err = ErrMetadataChangeOutOfScope
+			} else if !policy.allowsTransition(change) {
+				err = ErrMetadataTransitionNotAllowed
+			}
+
+			if err != nil {
+				violations = append(violations, MetadataViolation{Change: change, Err: err})
+				details = append(details, change.String())
+				offending = append(offending, change.Previous, change.Event)
+			}
+		}
+
+		if len(violations) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       MetadataPolicyErr{Violations: violations},
+			ErrorDetailKey:    "illegal metadata changes",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.IllegalMetadataChange,
+			OffendingEvents:   uniqueEvents(offending),
+		}
+	}
+}
+
+func (p MetadataPolicy) allowsTransition(change MetadataChange) bool {
+	if len(p.Transitions) == 0 {
+		return true
+	}
+
+	for _, transition := range p.Transitions {
+		if transition.matches(change) {
+			return true
+		}
+	}
+
+	return false
+}
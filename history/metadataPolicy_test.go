@@ -0,0 +1,162 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestMetadataPolicyValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-5 * time.Hour).Unix()
+	bootTime2 := now.Add(-2 * time.Hour).Unix()
+	event := func(id string, bootTime int64, minutesAgo int, sessionID string, key string, value string) interpreter.Event {
+		e := testCycleEvent(id, "online", bootTime, now.Add(-time.Duration(minutesAgo)*time.Minute), sessionID)
+		e.Metadata[key] = value
+		return e
+	}
+
+	tests := []struct {
+		description string
+		policy      MetadataPolicy
+		events      []interpreter.Event
+		expectedErr error
+	}{
+		{
+			description: "never unchanged",
+			policy:      MetadataPolicy{Key: "hw-mac", Scope: ChangeNever},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "hw-mac", "mac-1"),
+				event("2", bootTime2, 100, "b", "hw-mac", "mac-1"),
+			},
+		},
+		{
+			description: "never changed",
+			policy:      MetadataPolicy{Key: "hw-mac", Scope: ChangeNever},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "hw-mac", "mac-1"),
+				event("2", bootTime2, 100, "b", "hw-mac", "mac-2"),
+			},
+			expectedErr: ErrMetadataChangeOutOfScope,
+		},
+		{
+			description: "per-boot across reboot",
+			policy:      MetadataPolicy{Key: "fw-name", Scope: ChangePerBoot},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "fw-name", "fw-1"),
+				event("2", bootTime2, 100, "b", "fw-name", "fw-2"),
+			},
+		},
+		{
+			description: "per-boot within cycle",
+			policy:      MetadataPolicy{Key: "fw-name", Scope: ChangePerBoot},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "fw-name", "fw-1"),
+				event("2", bootTime1, 200, "b", "fw-name", "fw-2"),
+			},
+			expectedErr: ErrMetadataChangeOutOfScope,
+		},
+		{
+			description: "per-session between sessions",
+			policy:      MetadataPolicy{Key: "webpa-protocol", Scope: ChangePerSession},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "webpa-protocol", "p-1"),
+				event("2", bootTime1, 200, "b", "webpa-protocol", "p-2"),
+			},
+		},
+		{
+			description: "per-session within session",
+			policy:      MetadataPolicy{Key: "webpa-protocol", Scope: ChangePerSession},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "webpa-protocol", "p-1"),
+				event("2", bootTime1, 200, "a", "webpa-protocol", "p-2"),
+			},
+			expectedErr: ErrMetadataChangeOutOfScope,
+		},
+		{
+			description: "default scope",
+			policy:      MetadataPolicy{Key: "partner-id"},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "partner-id", "partner-1"),
+				event("2", bootTime1, 200, "a", "partner-id", "partner-2"),
+			},
+		},
+		{
+			description: "unknown scope",
+			policy:      MetadataPolicy{Key: "partner-id", Scope: "sometimes"},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "partner-id", "partner-1"),
+				event("2", bootTime1, 200, "a", "partner-id", "partner-2"),
+			},
+			expectedErr: ErrMetadataChangeOutOfScope,
+		},
+		{
+			description: "allowed transition",
+			policy: MetadataPolicy{Key: "fw-name", Scope: ChangePerBoot, Transitions: []MetadataTransition{
+				{From: "fw-1", To: "fw-2"},
+				{From: AnyMetadataValue, To: "fw-3"},
+			}},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "fw-name", "fw-1"),
+				event("2", bootTime2, 100, "b", "fw-name", "fw-2"),
+				event("3", now.Add(-time.Hour).Unix(), 50, "c", "fw-name", "fw-3"),
+			},
+		},
+		{
+			description: "transition not allowed",
+			policy: MetadataPolicy{Key: "fw-name", Scope: ChangeAny, Transitions: []MetadataTransition{
+				{From: "fw-1", To: "fw-2"},
+			}},
+			events: []interpreter.Event{
+				event("1", bootTime1, 280, "a", "fw-name", "fw-2"),
+				event("2", bootTime2, 100, "b", "fw-name", "fw-1"),
+			},
+			expectedErr: ErrMetadataTransitionNotAllowed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			valid, err := MetadataPolicyValidator([]MetadataPolicy{tc.policy}).Valid(tc.events)
+			if tc.expectedErr == nil {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			assert.True(errors.Is(err, ErrIllegalMetadataChange))
+			var cvErr CycleValidationErr
+			assert.True(errors.As(err, &cvErr))
+			assert.Equal(validation.IllegalMetadataChange, cvErr.Tag())
+			assert.Len(cvErr.Fields(), 1)
+
+			var policyErr MetadataPolicyErr
+			assert.True(errors.As(err, &policyErr))
+			if assert.Len(policyErr.Violations, 1) {
+				assert.Equal(tc.expectedErr, policyErr.Violations[0].Err)
+				assert.Equal("2", policyErr.Violations[0].Change.Event.TransactionUUID)
+			}
+		})
+	}
+}
+
+func TestParseChangeScope(t *testing.T) {
+	assert := assert.New(t)
+	scope, err := ParseChangeScope(" Per-Boot ")
+	assert.Nil(err)
+	assert.Equal(ChangePerBoot, scope)
+
+	scope, err = ParseChangeScope("")
+	assert.Nil(err)
+	assert.Equal(ChangeAny, scope)
+
+	_, err = ParseChangeScope("sometimes")
+	assert.True(errors.Is(err, ErrInvalidChangeScope))
+}
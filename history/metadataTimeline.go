@@ -0,0 +1,150 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// MetadataTimeline is how the values of chosen metadata keys changed across a device's history.
+type MetadataTimeline struct {
+	Keys []string
+
+	// Cycles are the values of the keys in each boot cycle, sorted from oldest to newest by boot-time.
+	Cycles []CycleMetadata
+
+	// Changes are every change to the value of a key, sorted from oldest to newest by the birthdate of the
+	// event the change was found in.
+	Changes []MetadataChange
+}
+
+// CycleMetadata is the values of metadata keys within a boot cycle.
+type CycleMetadata struct {
+	BootTime int64
+
+	// Values are the values of each key, in the order they first appeared in the cycle. Keys that no event
+	// in the cycle has are left out.
+	Values map[string][]string
+}
+
+// MetadataChange is a change to the value of a metadata key between two events.
+type MetadataChange struct {
+	Key  string
+	From string
+	To   string
+
+	// Previous is the last event with the old value, and Event is the first event with the new value.
+	Previous interpreter.Event
+	Event    interpreter.Event
+
+	// AcrossReboot is whether Previous and Event have valid boot-times that belong to different boot cycles,
+	// and AcrossSession is whether they belong to different sessions.
+	AcrossReboot  bool
+	AcrossSession bool
+}
+
+// String returns the key, the old and new values and the transaction uuid and birthdate of the event with the new value.
+func (c MetadataChange) String() string {
+	return fmt.Sprintf("%s: %q -> %q at %s (%s)", c.Key, c.From, c.To, c.Event.TransactionUUID, time.Unix(0, c.Event.Birthdate).UTC().Format(time.RFC3339Nano))
+}
+
+// NewMetadataTimeline walks the events from oldest to newest by birthdate and records the values of the keys in each
+// boot cycle, along with every event where the value of a key changed from the value in the event before it that has
+// the key. Events without a key are skipped for that key, so a key that is missing from some events is not a change.
+// Events without a valid boot-time are not part of any cycle, but are still checked for changes. Boot-times within the
+// boot-time tolerance of each other are considered the same.
+func NewMetadataTimeline(events []interpreter.Event, keys []string, opts ...Option) MetadataTimeline {
+	clusters := newOptions(opts).clusterBootTimes(events)
+	timeline := MetadataTimeline{Keys: keys}
+
+	cycles := SplitIntoCycles(events, opts...)
+	for i := len(cycles) - 1; i >= 0; i-- {
+		cycle := CycleMetadata{BootTime: cycles[i].BootTime, Values: make(map[string][]string)}
+		for _, event := range cycles[i].events {
+			for _, key := range keys {
+				if value, found := event.GetMetadataValue(key); found && !contains(cycle.Values[key], value) {
+					cycle.Values[key] = append(cycle.Values[key], value)
+				}
+			}
+		}
+
+		timeline.Cycles = append(timeline.Cycles, cycle)
+	}
+
+	sorted := make([]interpreter.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].Birthdate < sorted[b].Birthdate
+	})
+
+	previous := make(map[string]interpreter.Event)
+	for _, event := range sorted {
+		for _, key := range keys {
+			value, found := event.GetMetadataValue(key)
+			if !found {
+				continue
+			}
+
+			if last, seen := previous[key]; seen {
+				if lastValue, _ := last.GetMetadataValue(key); lastValue != value {
+					lastBootTime, _ := clusters.BootTime(last)
+					bootTime, _ := clusters.BootTime(event)
+					timeline.Changes = append(timeline.Changes, MetadataChange{
+						Key:           key,
+						From:          lastValue,
+						To:            value,
+						Previous:      last,
+						Event:         event,
+						AcrossReboot:  lastBootTime > 0 && bootTime > 0 && lastBootTime != bootTime,
+						AcrossSession: last.SessionID != event.SessionID,
+					})
+				}
+			}
+
+			previous[key] = event
+		}
+	}
+
+	return timeline
+}
+
+// ChangesFor returns the changes to the value of a key, sorted from oldest to newest.
+func (t MetadataTimeline) ChangesFor(key string) []MetadataChange {
+	var changes []MetadataChange
+	for _, change := range t.Changes {
+		if change.Key == key {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestNewMetadataTimeline(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime1 := now.Add(-5 * time.Hour).Unix()
+	bootTime2 := now.Add(-2 * time.Hour).Unix()
+	withMetadata := func(event interpreter.Event, metadata map[string]string) interpreter.Event {
+		for key, value := range metadata {
+			event.Metadata[key] = value
+		}
+		return event
+	}
+
+	events := []interpreter.Event{
+		withMetadata(testCycleEvent("6", "online", bootTime2, now.Add(-30*time.Minute), "c"), map[string]string{"fw-name": "fw-2", "webpa-protocol": "p-2"}),
+		withMetadata(testCycleEvent("5", "online", bootTime2, now.Add(-110*time.Minute), "b"), map[string]string{"fw-name": "fw-2", "webpa-protocol": "p-1"}),
+		withMetadata(testCycleEvent("4", "offline", bootTime1, now.Add(-150*time.Minute), "a"), map[string]string{"fw-name": "fw-1"}),
+		testCycleEvent("3", "operational", bootTime1, now.Add(-200*time.Minute), "a"),
+		withMetadata(testCycleEvent("2", "online", bootTime1, now.Add(-4*time.Hour), "a"), map[string]string{"fw-name": "fw-1", "webpa-protocol": "p-1"}),
+		withMetadata(testCycleEvent("1", "operational", 0, now.Add(-20*time.Minute), "c"), map[string]string{"fw-name": "fw-3"}),
+	}
+
+	keys := []string{"fw-name", "webpa-protocol", "partner-id"}
+	timeline := NewMetadataTimeline(events, keys)
+	assert.Equal(keys, timeline.Keys)
+	assert.Equal([]CycleMetadata{
+		{BootTime: bootTime1, Values: map[string][]string{"fw-name": {"fw-1"}, "webpa-protocol": {"p-1"}}},
+		{BootTime: bootTime2, Values: map[string][]string{"fw-name": {"fw-2"}, "webpa-protocol": {"p-1", "p-2"}}},
+	}, timeline.Cycles)
+
+	expected := []struct {
+		key           string
+		from          string
+		to            string
+		previous      string
+		event         string
+		acrossReboot  bool
+		acrossSession bool
+	}{
+		{key: "fw-name", from: "fw-1", to: "fw-2", previous: "4", event: "5", acrossReboot: true, acrossSession: true},
+		{key: "webpa-protocol", from: "p-1", to: "p-2", previous: "5", event: "6", acrossSession: true},
+		{key: "fw-name", from: "fw-2", to: "fw-3", previous: "6", event: "1", acrossSession: false},
+	}
+	if assert.Len(timeline.Changes, len(expected)) {
+		for i, e := range expected {
+			change := timeline.Changes[i]
+			assert.Equal(e.key, change.Key)
+			assert.Equal(e.from, change.From)
+			assert.Equal(e.to, change.To)
+			assert.Equal(e.previous, change.Previous.TransactionUUID)
+			assert.Equal(e.event, change.Event.TransactionUUID)
+			assert.Equal(e.acrossReboot, change.AcrossReboot, e.event)
+			assert.Equal(e.acrossSession, change.AcrossSession, e.event)
+		}
+	}
+
+	assert.Len(timeline.ChangesFor("fw-name"), 2)
+	assert.Empty(timeline.ChangesFor("partner-id"))
+	assert.Equal(`fw-name: "fw-1" -> "fw-2" at 5 (2021-03-02T16:10:01Z)`, timeline.Changes[0].String())
+
+	empty := NewMetadataTimeline(nil, keys)
+	assert.Empty(empty.Cycles)
+	assert.Empty(empty.Changes)
+}
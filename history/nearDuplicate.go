@@ -0,0 +1,161 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrNearDuplicates = errors.New("near-duplicate events found")
+)
+
+// DuplicateKey returns the key of an event, where events with the same key are considered duplicates of
+// each other. If false is returned, the event is not checked for duplicates.
+type DuplicateKey func(event interpreter.Event) (string, bool)
+
+// FingerprintKey returns a DuplicateKey that uses the event's fingerprint, so that events with the same
+// content, other than their transaction uuids and the ignored fields, are duplicates. Birthdates and session
+// ids are always left out, since a retransmitted event can carry a new birthdate and arrive in a new session;
+// how close together duplicates must be is decided by the validator's window instead.
+func FingerprintKey(ignoredFields ...string) DuplicateKey {
+	ignored := append([]string{interpreter.BirthdateField, interpreter.SessionIDField}, ignoredFields...)
+	return func(event interpreter.Event) (string, bool) {
+		return event.Fingerprint(ignored...), true
+	}
+}
+
+// EventTypeKey returns a DuplicateKey that uses the event's type, so that events of the same type are duplicates.
+// Events without an event type are skipped.
+func EventTypeKey() DuplicateKey {
+	return func(event interpreter.Event) (string, bool) {
+		eventType, err := event.EventType()
+		if err != nil {
+			return "", false
+		}
+
+		return strings.ToLower(eventType), true
+	}
+}
+
+// DuplicateCluster is a group of events with the same key that are duplicates of each other.
+type DuplicateCluster struct {
+	Key string
+
+	// Events are the events in the cluster, sorted from oldest to newest by birthdate.
+	Events []interpreter.Event
+}
+
+// String returns the event type and transaction uuids of the events in the cluster.
+func (c DuplicateCluster) String() string {
+	ids := make([]string, 0, len(c.Events))
+	for _, event := range c.Events {
+		ids = append(ids, event.TransactionUUID)
+	}
+
+	eventType, _ := c.Events[0].EventType()
+	return fmt.Sprintf("%s (%s)", eventType, strings.Join(ids, ", "))
+}
+
+// NearDuplicateErr is returned by NearDuplicateValidator and contains every cluster of duplicates found.
+type NearDuplicateErr struct {
+	Clusters []DuplicateCluster
+}
+
+func (e NearDuplicateErr) Error() string {
+	return fmt.Sprintf("%v: %d clusters", ErrNearDuplicates, len(e.Clusters))
+}
+
+func (e NearDuplicateErr) Unwrap() error {
+	return ErrNearDuplicates
+}
+
+// Tag implements the TaggedError interface.
+func (e NearDuplicateErr) Tag() validation.Tag {
+	return validation.DuplicateEvent
+}
+
+// NearDuplicateValidator returns a CycleValidatorFunc that validates that no two events with the same key
+// are within the window of each other, based on their birthdates. Duplicates are grouped into clusters, where
+// each event in a cluster is within the window of the event before it. Unlike TransactionUUIDValidator, this
+// finds events that were retransmitted with new transaction uuids. If key is nil, FingerprintKey is used.
+func NearDuplicateValidator(window time.Duration, key DuplicateKey) CycleValidatorFunc {
+	if key == nil {
+		key = FingerprintKey()
+	}
+
+	return func(events []interpreter.Event) (bool, error) {
+		eventsCopy := make([]interpreter.Event, len(events))
+		copy(eventsCopy, events)
+		sort.SliceStable(eventsCopy, func(a, b int) bool {
+			return eventsCopy[a].Birthdate < eventsCopy[b].Birthdate
+		})
+
+		var clusters []DuplicateCluster
+		// the index in clusters of the cluster that the latest event with each key belongs to
+		open := make(map[string]int)
+		latest := make(map[string]interpreter.Event)
+		for _, event := range eventsCopy {
+			k, ok := key(event)
+			if !ok {
+				continue
+			}
+
+			previous, found := latest[k]
+			latest[k] = event
+			if !found || time.Duration(event.Birthdate-previous.Birthdate) > window {
+				delete(open, k)
+				continue
+			}
+
+			if i, found := open[k]; found {
+				clusters[i].Events = append(clusters[i].Events, event)
+				continue
+			}
+
+			open[k] = len(clusters)
+			clusters = append(clusters, DuplicateCluster{Key: k, Events: []interpreter.Event{previous, event}})
+		}
+
+		if len(clusters) == 0 {
+			return true, nil
+		}
+
+		details := make([]string, 0, len(clusters))
+		var offending []interpreter.Event
+		for _, cluster := range clusters {
+			details = append(details, cluster.String())
+			offending = append(offending, cluster.Events...)
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       NearDuplicateErr{Clusters: clusters},
+			ErrorDetailKey:    "duplicate clusters",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.DuplicateEvent,
+			OffendingEvents:   offending,
+		}
+	}
+}
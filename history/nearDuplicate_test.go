@@ -0,0 +1,140 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestNearDuplicateValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	online := testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a")
+	retransmitted := online
+	retransmitted.TransactionUUID = "2"
+	retransmittedAgain := online
+	retransmittedAgain.TransactionUUID = "3"
+	retransmittedLater := online
+	retransmittedLater.TransactionUUID = "4"
+	retransmittedLater.Birthdate = now.Add(-108 * time.Minute).UnixNano()
+	retransmittedLater.SessionID = "b"
+	retransmittedMuchLater := online
+	retransmittedMuchLater.TransactionUUID = "5"
+	retransmittedMuchLater.Birthdate = now.Add(-60 * time.Minute).UnixNano()
+	trusted := online
+	trusted.TransactionUUID = "6"
+	trusted.Metadata = map[string]string{interpreter.BootTimeKey: fmt.Sprint(bootTime), "/trust": "1000"}
+
+	tests := []struct {
+		description      string
+		window           time.Duration
+		key              DuplicateKey
+		events           []interpreter.Event
+		expectedClusters [][]string
+	}{
+		{
+			description: "no duplicates",
+			events: []interpreter.Event{
+				online,
+				testCycleEvent("4", "operational", bootTime, now.Add(-100*time.Minute), "a"),
+			},
+		},
+		{
+			description: "empty",
+		},
+		{
+			description:      "same content",
+			events:           []interpreter.Event{retransmitted, online, retransmittedAgain},
+			expectedClusters: [][]string{{"2", "1", "3"}},
+		},
+		{
+			description:      "same content with new birthdate within window",
+			window:           5 * time.Minute,
+			events:           []interpreter.Event{online, retransmittedLater},
+			expectedClusters: [][]string{{"1", "4"}},
+		},
+		{
+			description: "same content with new birthdate outside window",
+			window:      5 * time.Minute,
+			events:      []interpreter.Event{online, retransmittedMuchLater},
+		},
+		{
+			description: "same type outside window",
+			window:      5 * time.Minute,
+			key:         EventTypeKey(),
+			events: []interpreter.Event{
+				online,
+				testCycleEvent("4", "online", bootTime, now.Add(-100*time.Minute), "b"),
+			},
+		},
+		{
+			description: "same type within window",
+			window:      5 * time.Minute,
+			key:         EventTypeKey(),
+			events: []interpreter.Event{
+				online,
+				testCycleEvent("4", "ONLINE", bootTime, now.Add(-107*time.Minute), "b"),
+				testCycleEvent("5", "operational", bootTime, now.Add(-106*time.Minute), "b"),
+				testCycleEvent("6", "online", bootTime, now.Add(-103*time.Minute), "c"),
+				testCycleEvent("7", "online", bootTime, now.Add(-90*time.Minute), "d"),
+				testCycleEvent("8", "online", bootTime, now.Add(-88*time.Minute), "e"),
+				testCycleEvent("9", "operational", bootTime, now.Add(-80*time.Minute), "e"),
+			},
+			expectedClusters: [][]string{{"1", "4", "6"}, {"7", "8"}},
+		},
+		{
+			description:      "same content ignoring field",
+			key:              FingerprintKey("metadata:/trust"),
+			events:           []interpreter.Event{online, trusted},
+			expectedClusters: [][]string{{"1", "6"}},
+		},
+		{
+			description: "different content",
+			events:      []interpreter.Event{online, trusted},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			valid, err := NearDuplicateValidator(tc.window, tc.key).Valid(tc.events)
+			if len(tc.expectedClusters) == 0 {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			var cvErr CycleValidationErr
+			assert.True(errors.As(err, &cvErr))
+			assert.Equal(validation.DuplicateEvent, cvErr.Tag())
+			assert.Len(cvErr.Fields(), len(tc.expectedClusters))
+
+			var dupErr NearDuplicateErr
+			assert.True(errors.As(err, &dupErr))
+			assert.True(errors.Is(err, ErrNearDuplicates))
+			var clusters [][]string
+			for _, cluster := range dupErr.Clusters {
+				clusters = append(clusters, testEventIDs(cluster.Events))
+			}
+			assert.Equal(tc.expectedClusters, clusters)
+		})
+	}
+}
+
+func TestDuplicateClusterString(t *testing.T) {
+	now := time.Now()
+	cluster := DuplicateCluster{
+		Events: []interpreter.Event{
+			testCycleEvent("1", "online", now.Unix(), now, "a"),
+			testCycleEvent("2", "online", now.Unix(), now, "a"),
+		},
+	}
+	assert.Equal(t, "online (1, 2)", cluster.String())
+}
@@ -0,0 +1,153 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+var (
+	ErrInvalidBootTime = errors.New("invalid boot-time")
+)
+
+// OnlineKind describes why a device came online.
+type OnlineKind int
+
+const (
+	// UnknownOnline is an online event that could not be classified.
+	UnknownOnline OnlineKind = iota
+
+	// FirstBoot is an online event with no earlier events with boot-times in the history,
+	// most likely the first time the device came online in the history.
+	FirstBoot
+
+	// TrueReboot is an online event with a newer boot-time than the event before it.
+	TrueReboot
+
+	// Reconnect is an online event with the same boot-time as the event before it,
+	// meaning that the device reconnected without rebooting.
+	Reconnect
+)
+
+func (k OnlineKind) String() string {
+	switch k {
+	case FirstBoot:
+		return "first_boot"
+	case TrueReboot:
+		return "true_reboot"
+	case Reconnect:
+		return "reconnect"
+	default:
+		return "unknown"
+	}
+}
+
+// OnlineClassification is the kind of an online event along with what it was compared to.
+type OnlineClassification struct {
+	Event interpreter.Event
+	Kind  OnlineKind
+
+	// Previous is the latest event born before Event that has a valid boot-time, if one was found.
+	Previous    interpreter.Event
+	HasPrevious bool
+
+	// Reason is why Event could not be classified when Kind is UnknownOnline.
+	Reason error
+}
+
+// OnlineCounts is the number of online events of each kind.
+type OnlineCounts map[OnlineKind]int
+
+// ClassifyOnlineEvents labels every online event in a list of events as a first boot, a true reboot,
+// a reconnect or unknown, by comparing its boot-time with the boot-time of the latest event born before it.
+// Online events are the event types with the session start role in the event type registry, and boot-times
+// within the boot-time tolerance of each other are considered the same. The returned classifications are
+// sorted from oldest to newest by birthdate.
+func ClassifyOnlineEvents(events []interpreter.Event, opts ...Option) []OnlineClassification {
+	o := newOptions(opts)
+	clusters := o.clusterBootTimes(events)
+	eventsCopy := make([]interpreter.Event, len(events))
+	copy(eventsCopy, events)
+	sort.SliceStable(eventsCopy, func(a, b int) bool {
+		return eventsCopy[a].Birthdate < eventsCopy[b].Birthdate
+	})
+
+	var classifications []OnlineClassification
+	var previous *interpreter.Event
+	var previousBootTime int64
+	for i, event := range eventsCopy {
+		bootTime, err := clusters.BootTime(event)
+		if err == nil && bootTime <= 0 {
+			err = ErrInvalidBootTime
+		}
+
+		if o.hasRole(event, interpreter.SessionStartRole) {
+			classification := OnlineClassification{Event: event}
+			if previous != nil {
+				classification.Previous = *previous
+				classification.HasPrevious = true
+			}
+
+			switch {
+			case err != nil:
+				classification.Reason = err
+			case previous == nil:
+				classification.Kind = FirstBoot
+			case bootTime == previousBootTime:
+				classification.Kind = Reconnect
+			case bootTime > previousBootTime:
+				classification.Kind = TrueReboot
+			default:
+				classification.Reason = ErrBootTimeRegression
+			}
+
+			classifications = append(classifications, classification)
+		}
+
+		if err == nil {
+			previous = &eventsCopy[i]
+			previousBootTime = bootTime
+		}
+	}
+
+	return classifications
+}
+
+// CycleOnlineCounts classifies the online events in a list of events with ClassifyOnlineEvents and counts
+// them by kind for each boot cycle, keyed by the boot-time representing the cycle. Online events without
+// a valid boot-time are counted under the boot-time 0.
+func CycleOnlineCounts(events []interpreter.Event, opts ...Option) map[int64]OnlineCounts {
+	clusters := newOptions(opts).clusterBootTimes(events)
+	counts := make(map[int64]OnlineCounts)
+	for _, classification := range ClassifyOnlineEvents(events, opts...) {
+		bootTime, _ := clusters.BootTime(classification.Event)
+		if bootTime < 0 {
+			bootTime = 0
+		}
+
+		if counts[bootTime] == nil {
+			counts[bootTime] = make(OnlineCounts)
+		}
+		counts[bootTime][classification.Kind]++
+	}
+
+	return counts
+}
@@ -0,0 +1,70 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestClassifyOnlineEvents(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime1 := now.Add(-3 * time.Hour).Unix()
+	bootTime2 := now.Add(-2 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("8", "online", bootTime1, now.Add(-10*time.Minute), "f"),
+		testCycleEvent("7", "online", 0, now.Add(-20*time.Minute), "e"),
+		testCycleEvent("6", "online", bootTime2, now.Add(-30*time.Minute), "d"),
+		testCycleEvent("5", "offline", bootTime2, now.Add(-40*time.Minute), "c"),
+		testCycleEvent("4", "online", bootTime2, now.Add(-110*time.Minute), "c"),
+		testCycleEvent("3", "offline", bootTime1, now.Add(-130*time.Minute), "b"),
+		testCycleEvent("2", "online", bootTime1+1, now.Add(-170*time.Minute), "b"),
+		testCycleEvent("1", "online", bootTime1, now.Add(-175*time.Minute), "a"),
+	}
+
+	classifications := ClassifyOnlineEvents(events, WithBootTimeTolerance(time.Second))
+	assert.Len(classifications, 6)
+	expected := []struct {
+		id       string
+		kind     OnlineKind
+		previous string
+		reason   error
+	}{
+		{id: "1", kind: FirstBoot},
+		{id: "2", kind: Reconnect, previous: "1"},
+		{id: "4", kind: TrueReboot, previous: "3"},
+		{id: "6", kind: Reconnect, previous: "5"},
+		{id: "7", kind: UnknownOnline, previous: "6", reason: ErrInvalidBootTime},
+		{id: "8", kind: UnknownOnline, previous: "6", reason: ErrBootTimeRegression},
+	}
+
+	for i, e := range expected {
+		classification := classifications[i]
+		assert.Equal(e.id, classification.Event.TransactionUUID)
+		assert.Equal(e.kind, classification.Kind, e.id)
+		assert.Equal(e.reason, classification.Reason, e.id)
+		assert.Equal(len(e.previous) > 0, classification.HasPrevious, e.id)
+		assert.Equal(e.previous, classification.Previous.TransactionUUID, e.id)
+	}
+
+	counts := CycleOnlineCounts(events, WithBootTimeTolerance(time.Second))
+	assert.Equal(map[int64]OnlineCounts{
+		0:         {UnknownOnline: 1},
+		bootTime1: {FirstBoot: 1, Reconnect: 1, UnknownOnline: 1},
+		bootTime2: {TrueReboot: 1, Reconnect: 1},
+	}, counts)
+
+	assert.Empty(ClassifyOnlineEvents(nil))
+}
+
+func TestOnlineKindString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("unknown", UnknownOnline.String())
+	assert.Equal("first_boot", FirstBoot.String())
+	assert.Equal("true_reboot", TrueReboot.String())
+	assert.Equal("reconnect", Reconnect.String())
+	assert.Equal("unknown", OnlineKind(100).String())
+}
@@ -0,0 +1,114 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// Option configures the parsers, comparators, finders and validators in this package.
+type Option func(*options)
+
+type options struct {
+	bootTimeTolerance int64
+	rebootDefinition  *RebootDefinition
+	eventTypes        *interpreter.EventTypeRegistry
+	clockResetBefore  int64
+	rebootReasonKey   string
+}
+
+// WithBootTimeTolerance sets how far apart, in seconds, two boot-times can be while still
+// belonging to the same boot cycle. Devices often report boot-times that differ by a second
+// or two within the same boot. The default tolerance is zero, meaning boot-times must be equal.
+func WithBootTimeTolerance(tolerance time.Duration) Option {
+	return func(o *options) {
+		if tolerance < 0 {
+			tolerance = -1 * tolerance
+		}
+
+		o.bootTimeTolerance = int64(tolerance / time.Second)
+	}
+}
+
+// WithRebootDefinition sets the events that mark the start and end of a reboot.
+// The default is the RegistryRebootDefinition of the event type registry used.
+func WithRebootDefinition(definition RebootDefinition) Option {
+	return func(o *options) {
+		o.rebootDefinition = &definition
+	}
+}
+
+// WithEventTypeRegistry sets the registry used to find out what each event type means, such as
+// which event types start and end a session. The default is interpreter.DefaultEventTypeRegistry.
+func WithEventTypeRegistry(registry *interpreter.EventTypeRegistry) Option {
+	return func(o *options) {
+		o.eventTypes = registry
+	}
+}
+
+// WithClockResetBefore sets the time before which boot-times are treated as defaults reported by a device
+// whose clock was reset, rather than real boot-times. The default is DefaultClockResetBefore.
+func WithClockResetBefore(before time.Time) Option {
+	return func(o *options) {
+		o.clockResetBefore = before.Unix()
+	}
+}
+
+// WithRebootReasonKey sets the metadata key that devices report the reason for their last reboot under.
+// The default is DefaultRebootReasonKey.
+func WithRebootReasonKey(key string) Option {
+	return func(o *options) {
+		o.rebootReasonKey = key
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	if o.eventTypes == nil {
+		o.eventTypes = interpreter.DefaultEventTypeRegistry()
+	}
+
+	if o.clockResetBefore == 0 {
+		o.clockResetBefore = DefaultClockResetBefore.Unix()
+	}
+
+	if len(o.rebootReasonKey) == 0 {
+		o.rebootReasonKey = DefaultRebootReasonKey
+	}
+
+	if o.rebootDefinition == nil {
+		definition := RegistryRebootDefinition(o.eventTypes)
+		o.rebootDefinition = &definition
+	}
+
+	return o
+}
+
+// hasRole returns whether the event's type has the role in the event type registry.
+func (o options) hasRole(event interpreter.Event, role interpreter.Role) bool {
+	eventType, err := event.EventType()
+	return err == nil && o.eventTypes.HasRole(eventType, role)
+}
@@ -0,0 +1,175 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// Reboot is the transition from one boot cycle to the next. Times of events that
+// were not found in the history are left as the zero time.
+type Reboot struct {
+	BootTime         time.Time
+	PreviousBootTime time.Time
+
+	// Start and End are the birthdates of the events marking the start and end of the reboot,
+	// as described by the RebootDefinition used.
+	Start time.Time
+	End   time.Time
+
+	// RebootPending and LastOffline are the birthdates of the last reboot-pending and
+	// offline events of the previous cycle, or of the event types with the shutdown intent
+	// and session end roles in the event type registry used.
+	RebootPending time.Time
+	LastOffline   time.Time
+
+	// ScheduledDelay is how long after it was sent the last reboot-pending event of the previous cycle scheduled
+	// the reboot for, parsed from its destination with Event.RebootSchedule, if Scheduled is true.
+	ScheduledDelay time.Duration
+	Scheduled      bool
+
+	// FirstOnline, Operational and FullyManageable are the birthdates of the first online,
+	// operational and fully-manageable events of the new cycle. FirstOnline is the first event
+	// with the session start role in the event type registry used.
+	FirstOnline     time.Time
+	Operational     time.Time
+	FullyManageable time.Time
+
+	// Reason is the reason for the reboot reported by the device, which is the value of the reboot reason
+	// metadata key of the oldest event in the new cycle that has it. See WithRebootReasonKey.
+	Reason string
+
+	// Events are the events relevant to the reboot, as returned by RebootParser,
+	// sorted from newest to oldest primarily by boot-time, and then by birthdate.
+	Events []interpreter.Event
+}
+
+// Reboots returns every reboot found in a list of events, sorted from newest to oldest.
+// A reboot is found wherever one boot cycle is followed by another, with cycles split
+// the same way as SplitIntoCycles. The events marking the start and end of each reboot can be
+// changed with WithRebootDefinition.
+func Reboots(events []interpreter.Event, opts ...Option) []Reboot {
+	cycles := SplitIntoCycles(events, opts...)
+	if len(cycles) < 2 {
+		return nil
+	}
+
+	o := newOptions(opts)
+	reboots := make([]Reboot, 0, len(cycles)-1)
+	for i := 0; i < len(cycles)-1; i++ {
+		reboots = append(reboots, newReboot(cycles[i+1], cycles[i], o))
+	}
+
+	return reboots
+}
+
+func newReboot(previousCycle Cycle, cycle Cycle, o options) Reboot {
+	reboot := Reboot{
+		BootTime:         time.Unix(cycle.BootTime, 0),
+		PreviousBootTime: time.Unix(previousCycle.BootTime, 0),
+	}
+
+	for _, event := range previousCycle.events {
+		if o.hasRole(event, interpreter.ShutdownIntentRole) {
+			reboot.RebootPending = time.Unix(0, event.Birthdate)
+			_, delay, err := event.RebootSchedule()
+			reboot.ScheduledDelay, reboot.Scheduled = delay, err == nil
+		}
+
+		if o.hasRole(event, interpreter.SessionEndRole) {
+			reboot.LastOffline = time.Unix(0, event.Birthdate)
+		}
+	}
+
+	for i := len(cycle.events) - 1; i >= 0; i-- {
+		event := cycle.events[i]
+		if reason, found := event.GetMetadataValue(o.rebootReasonKey); found {
+			reboot.Reason = reason
+		}
+
+		if o.hasRole(event, interpreter.SessionStartRole) {
+			reboot.FirstOnline = time.Unix(0, event.Birthdate)
+		}
+
+		switch eventType, _ := event.EventType(); eventType {
+		case interpreter.OperationalEventType:
+			reboot.Operational = time.Unix(0, event.Birthdate)
+		case interpreter.FullyManageableEventType:
+			reboot.FullyManageable = time.Unix(0, event.Birthdate)
+		}
+	}
+
+	definition := o.rebootDefinition
+
+	previousEvents := previousCycle.NewestToOldest()
+	if i := findByPriority(previousEvents, definition.Start, false); i > -1 {
+		reboot.Start = time.Unix(0, previousEvents[i].Birthdate)
+	}
+
+	events := cycle.NewestToOldest()
+	if i := findByPriority(events, definition.End, true); i > -1 {
+		reboot.End = time.Unix(0, events[i].Birthdate)
+	}
+
+	rebootStart := definition.startEvents(previousEvents)
+	rebootEnd := definition.endEvents(events)
+	reboot.Events = append(rebootEnd, rebootStart...)
+	return reboot
+}
+
+// Duration returns the time from the event marking the start of the reboot to the event marking the end.
+func (r Reboot) Duration() (time.Duration, bool) {
+	return durationBetween(r.Start, r.End)
+}
+
+// ShutdownDuration returns the time from the reboot-pending event to the last offline event.
+func (r Reboot) ShutdownDuration() (time.Duration, bool) {
+	return durationBetween(r.RebootPending, r.LastOffline)
+}
+
+// OfflineDuration returns the time from the last offline event to the first online event.
+func (r Reboot) OfflineDuration() (time.Duration, bool) {
+	return durationBetween(r.LastOffline, r.FirstOnline)
+}
+
+// BootToOnline returns the time from the boot-time to the first online event.
+func (r Reboot) BootToOnline() (time.Duration, bool) {
+	return durationBetween(r.BootTime, r.FirstOnline)
+}
+
+// BootToOperational returns the time from the boot-time to the first operational event.
+func (r Reboot) BootToOperational() (time.Duration, bool) {
+	return durationBetween(r.BootTime, r.Operational)
+}
+
+// BootToFullyManageable returns the time from the boot-time to the first fully-manageable event.
+func (r Reboot) BootToFullyManageable() (time.Duration, bool) {
+	return durationBetween(r.BootTime, r.FullyManageable)
+}
+
+// durationBetween returns the time from start to end. False is returned
+// if either time was not found.
+func durationBetween(start time.Time, end time.Time) (time.Duration, bool) {
+	if start.IsZero() || end.IsZero() {
+		return 0, false
+	}
+
+	return end.Sub(start), true
+}
@@ -0,0 +1,236 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+// DefaultRebootReasonKey is the metadata key that devices report the reason for their last reboot under.
+const DefaultRebootReasonKey = "hw-last-reboot-reason"
+
+var (
+	ErrRebootReasonMismatch   = errors.New("reboot reason does not match reboot-pending events")
+	ErrPlannedWithoutPending  = fmt.Errorf("%w: planned reboot without a reboot-pending event", ErrRebootReasonMismatch)
+	ErrPendingBeforeUnplanned = fmt.Errorf("%w: reboot-pending event before an unplanned reboot", ErrRebootReasonMismatch)
+)
+
+// RebootReason is what a reboot reason reported by a device means.
+type RebootReason struct {
+	// Category groups reboot reasons that mean the same thing, such as "user", "firmware-upgrade", "crash" or "watchdog".
+	Category string
+
+	// Planned is whether a reboot with this reason is expected to be announced with a reboot-pending event.
+	Planned bool
+}
+
+// RebootTaxonomy maps the reboot reasons reported by devices to what they mean.
+type RebootTaxonomy map[string]RebootReason
+
+// DefaultRebootTaxonomy returns the taxonomy used when one is not given.
+func DefaultRebootTaxonomy() RebootTaxonomy {
+	return RebootTaxonomy{
+		"user":             {Category: "user", Planned: true},
+		"scheduled":        {Category: "scheduled", Planned: true},
+		"firmware-upgrade": {Category: "firmware-upgrade", Planned: true},
+		"factory-reset":    {Category: "factory-reset", Planned: true},
+		"crash":            {Category: "crash", Planned: false},
+		"kernel-panic":     {Category: "crash", Planned: false},
+		"watchdog":         {Category: "watchdog", Planned: false},
+		"power-loss":       {Category: "power-loss", Planned: false},
+	}
+}
+
+// Lookup returns what a reported reboot reason means, ignoring case and surrounding whitespace.
+// False is returned if the reason is not in the taxonomy.
+func (t RebootTaxonomy) Lookup(reason string) (RebootReason, bool) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) == 0 {
+		return RebootReason{}, false
+	}
+
+	if r, found := t[reason]; found {
+		return r, true
+	}
+
+	for key, r := range t {
+		if strings.EqualFold(key, reason) {
+			return r, true
+		}
+	}
+
+	return RebootReason{}, false
+}
+
+// RebootPolicy configures how reboots are classified as planned or unplanned.
+type RebootPolicy struct {
+	// Taxonomy maps the reboot reasons reported by devices to what they mean. DefaultRebootTaxonomy is used if it is empty.
+	Taxonomy RebootTaxonomy
+
+	// MaxPendingDelay is the longest a reboot can be scheduled for after its reboot-pending event, which is the delay
+	// encoded in the reboot-pending event's destination. A reboot-pending event scheduling the reboot further ahead
+	// than that is not considered to announce the reboot, while one without a schedule always announces it.
+	// A MaxPendingDelay of 0 does not limit the delay.
+	MaxPendingDelay time.Duration
+}
+
+// RebootClassification is whether a reboot was planned, along with what the decision was based on.
+type RebootClassification struct {
+	Reboot  Reboot
+	Planned bool
+
+	// Reason is what the reboot reason reported by the device means, if KnownReason is true.
+	Reason      RebootReason
+	KnownReason bool
+
+	// Announced is whether the reboot was announced by a reboot-pending event within the policy's MaxPendingDelay,
+	// and PendingDelay is how long the reboot-pending event scheduled the reboot for, which is the Reboot's
+	// ScheduledDelay.
+	Announced    bool
+	PendingDelay time.Duration
+
+	// Mismatch is ErrPlannedWithoutPending or ErrPendingBeforeUnplanned if the reboot reason disagrees
+	// with the reboot-pending events, or nil otherwise.
+	Mismatch error
+}
+
+// String returns the boot-time of the reboot along with what it was classified as and why.
+func (c RebootClassification) String() string {
+	kind := "unplanned"
+	if c.Planned {
+		kind = "planned"
+	}
+
+	reason := c.Reboot.Reason
+	if len(reason) == 0 {
+		reason = "none"
+	}
+
+	pending := "no reboot-pending"
+	if c.Announced && c.Reboot.Scheduled {
+		pending = fmt.Sprintf("reboot-pending scheduled %s ahead", c.PendingDelay)
+	} else if c.Announced {
+		pending = "reboot-pending"
+	}
+
+	return fmt.Sprintf("%d: %s, reason %s, %s", c.Reboot.BootTime.Unix(), kind, reason, pending)
+}
+
+// RebootReasonMismatchErr is returned by RebootReasonValidator and contains every reboot whose reason
+// disagrees with its reboot-pending events.
+type RebootReasonMismatchErr struct {
+	Mismatches []RebootClassification
+}
+
+func (e RebootReasonMismatchErr) Error() string {
+	return fmt.Sprintf("%v: %d reboots found", ErrRebootReasonMismatch, len(e.Mismatches))
+}
+
+func (e RebootReasonMismatchErr) Unwrap() error {
+	return ErrRebootReasonMismatch
+}
+
+// Tag implements the TaggedError interface.
+func (e RebootReasonMismatchErr) Tag() validation.Tag {
+	return validation.RebootReasonMismatch
+}
+
+// ClassifyReboots finds every reboot in a list of events with Reboots and classifies each one as planned or unplanned.
+// A reboot with a reason found in the policy's taxonomy is planned if the reason is, and a reboot with an unknown or
+// missing reason is planned if it was announced by a reboot-pending event, which is the event type with the shutdown
+// intent role in the event type registry. The reason is read from the metadata key set with WithRebootReasonKey.
+// The returned classifications are sorted from newest to oldest.
+func ClassifyReboots(events []interpreter.Event, policy RebootPolicy, opts ...Option) []RebootClassification {
+	reboots := Reboots(events, opts...)
+	if len(reboots) == 0 {
+		return nil
+	}
+
+	if len(policy.Taxonomy) == 0 {
+		policy.Taxonomy = DefaultRebootTaxonomy()
+	}
+
+	classifications := make([]RebootClassification, 0, len(reboots))
+	for _, reboot := range reboots {
+		classifications = append(classifications, classifyReboot(reboot, policy))
+	}
+
+	return classifications
+}
+
+func classifyReboot(reboot Reboot, policy RebootPolicy) RebootClassification {
+	classification := RebootClassification{Reboot: reboot}
+	if !reboot.RebootPending.IsZero() {
+		classification.PendingDelay = reboot.ScheduledDelay
+		classification.Announced = policy.MaxPendingDelay <= 0 || !reboot.Scheduled || reboot.ScheduledDelay <= policy.MaxPendingDelay
+	}
+
+	classification.Reason, classification.KnownReason = policy.Taxonomy.Lookup(reboot.Reason)
+	if !classification.KnownReason {
+		classification.Planned = classification.Announced
+		return classification
+	}
+
+	classification.Planned = classification.Reason.Planned
+	switch {
+	case classification.Planned && !classification.Announced:
+		classification.Mismatch = ErrPlannedWithoutPending
+	case !classification.Planned && classification.Announced:
+		classification.Mismatch = ErrPendingBeforeUnplanned
+	}
+
+	return classification
+}
+
+// RebootReasonValidator returns a CycleValidatorFunc that validates that the reason each device reports for a reboot
+// agrees with whether the reboot was announced by a reboot-pending event, such as a reboot with the reason "scheduled"
+// that had no reboot-pending event, or a reboot with the reason "crash" that did. Reboots are classified with
+// ClassifyReboots, and reboots with reasons that are not in the taxonomy are skipped. It is meant to be run on
+// a device's whole history.
+func RebootReasonValidator(policy RebootPolicy, opts ...Option) CycleValidatorFunc {
+	return func(events []interpreter.Event) (bool, error) {
+		var mismatches []RebootClassification
+		var details []string
+		var offending []interpreter.Event
+		for _, classification := range ClassifyReboots(events, policy, opts...) {
+			if classification.Mismatch != nil {
+				mismatches = append(mismatches, classification)
+				details = append(details, classification.String())
+				offending = append(offending, classification.Reboot.Events...)
+			}
+		}
+
+		if len(mismatches) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       RebootReasonMismatchErr{Mismatches: mismatches},
+			ErrorDetailKey:    "reboot reason mismatches",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.RebootReasonMismatch,
+			OffendingEvents:   uniqueEvents(offending),
+		}
+	}
+}
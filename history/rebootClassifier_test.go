@@ -0,0 +1,224 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestClassifyReboots(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-10 * time.Hour)
+	bootTime2 := now.Add(-2 * time.Hour)
+	shutdown := bootTime2.Add(-time.Minute)
+	pendingAt := shutdown.Add(-10 * time.Minute)
+	schedule := func(delay string) string {
+		return fmt.Sprintf("reboot-pending/%d/%s", pendingAt.Unix(), delay)
+	}
+
+	// one reboot from bootTime1 to bootTime2, with a reboot-pending event 10 minutes before the offline event
+	// of the previous cycle and the reason reported by the online event of the new cycle
+	rebootEvents := func(pending string, offline bool, reasonKey string, reason string) []interpreter.Event {
+		events := []interpreter.Event{testCycleEvent("1", "online", bootTime1.Unix(), bootTime1.Add(time.Minute), "a")}
+		if len(pending) > 0 {
+			events = append(events, testCycleEvent("2", pending, bootTime1.Unix(), pendingAt, "a"))
+		}
+		if offline {
+			events = append(events, testCycleEvent("3", "offline", bootTime1.Unix(), shutdown, "a"))
+		}
+
+		online := testCycleEvent("4", "online", bootTime2.Unix(), bootTime2.Add(time.Minute), "b")
+		if len(reason) > 0 {
+			online.Metadata[reasonKey] = reason
+		}
+		return append(events, online)
+	}
+
+	tests := []struct {
+		description       string
+		events            []interpreter.Event
+		policy            RebootPolicy
+		opts              []Option
+		expectedPlanned   bool
+		expectedKnown     bool
+		expectedCategory  string
+		expectedAnnounced bool
+		expectedDelay     time.Duration
+		expectedMismatch  error
+	}{
+		{
+			description:       "scheduled with reboot-pending",
+			events:            rebootEvents(schedule("10m"), true, DefaultRebootReasonKey, "scheduled"),
+			expectedPlanned:   true,
+			expectedKnown:     true,
+			expectedCategory:  "scheduled",
+			expectedAnnounced: true,
+			expectedDelay:     10 * time.Minute,
+		},
+		{
+			description:      "scheduled without reboot-pending",
+			events:           rebootEvents("", true, DefaultRebootReasonKey, "scheduled"),
+			expectedPlanned:  true,
+			expectedKnown:    true,
+			expectedCategory: "scheduled",
+			expectedMismatch: ErrPlannedWithoutPending,
+		},
+		{
+			description:       "crash with reboot-pending",
+			events:            rebootEvents(schedule("10m"), true, DefaultRebootReasonKey, "CRASH"),
+			expectedKnown:     true,
+			expectedCategory:  "crash",
+			expectedAnnounced: true,
+			expectedDelay:     10 * time.Minute,
+			expectedMismatch:  ErrPendingBeforeUnplanned,
+		},
+		{
+			description:      "watchdog without reboot-pending",
+			events:           rebootEvents("", true, DefaultRebootReasonKey, "watchdog"),
+			expectedKnown:    true,
+			expectedCategory: "watchdog",
+		},
+		{
+			description:       "unknown reason with reboot-pending",
+			events:            rebootEvents(schedule("10m"), true, DefaultRebootReasonKey, "reboot-reason-1"),
+			expectedPlanned:   true,
+			expectedAnnounced: true,
+			expectedDelay:     10 * time.Minute,
+		},
+		{
+			description: "missing reason without reboot-pending",
+			events:      rebootEvents("", false, DefaultRebootReasonKey, ""),
+		},
+		{
+			description:      "reboot scheduled too far ahead",
+			events:           rebootEvents(schedule("2h"), true, DefaultRebootReasonKey, "user"),
+			policy:           RebootPolicy{MaxPendingDelay: time.Hour},
+			expectedPlanned:  true,
+			expectedKnown:    true,
+			expectedCategory: "user",
+			expectedDelay:    2 * time.Hour,
+			expectedMismatch: ErrPlannedWithoutPending,
+		},
+		{
+			description:       "no offline event",
+			events:            rebootEvents(schedule("10m"), false, DefaultRebootReasonKey, "user"),
+			policy:            RebootPolicy{MaxPendingDelay: time.Hour},
+			expectedPlanned:   true,
+			expectedKnown:     true,
+			expectedCategory:  "user",
+			expectedAnnounced: true,
+			expectedDelay:     10 * time.Minute,
+		},
+		{
+			description:       "delay in seconds",
+			events:            rebootEvents(schedule("1800"), true, DefaultRebootReasonKey, "user"),
+			policy:            RebootPolicy{MaxPendingDelay: time.Hour},
+			expectedPlanned:   true,
+			expectedKnown:     true,
+			expectedCategory:  "user",
+			expectedAnnounced: true,
+			expectedDelay:     30 * time.Minute,
+		},
+		{
+			description:       "reboot-pending without schedule",
+			events:            rebootEvents("reboot-pending", true, DefaultRebootReasonKey, "user"),
+			policy:            RebootPolicy{MaxPendingDelay: time.Hour},
+			expectedPlanned:   true,
+			expectedKnown:     true,
+			expectedCategory:  "user",
+			expectedAnnounced: true,
+		},
+		{
+			description: "custom taxonomy and key",
+			events:      rebootEvents("", true, "last-reason", "reboot-reason-1"),
+			policy: RebootPolicy{Taxonomy: RebootTaxonomy{
+				"reboot-reason-1": {Category: "maintenance", Planned: true},
+			}},
+			opts:             []Option{WithRebootReasonKey("last-reason")},
+			expectedPlanned:  true,
+			expectedKnown:    true,
+			expectedCategory: "maintenance",
+			expectedMismatch: ErrPlannedWithoutPending,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			classifications := ClassifyReboots(tc.events, tc.policy, tc.opts...)
+			if !assert.Len(classifications, 1) {
+				return
+			}
+
+			classification := classifications[0]
+			assert.Equal(bootTime2.Unix(), classification.Reboot.BootTime.Unix())
+			assert.Equal(tc.expectedPlanned, classification.Planned)
+			assert.Equal(tc.expectedKnown, classification.KnownReason)
+			assert.Equal(tc.expectedCategory, classification.Reason.Category)
+			assert.Equal(tc.expectedAnnounced, classification.Announced)
+			assert.Equal(tc.expectedDelay, classification.PendingDelay)
+			assert.Equal(tc.expectedMismatch, classification.Mismatch)
+		})
+	}
+
+	assert.Empty(t, ClassifyReboots(rebootEvents("", true, DefaultRebootReasonKey, "")[:1], RebootPolicy{}))
+}
+
+func TestRebootReasonValidator(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime1 := now.Add(-10 * time.Hour).Unix()
+	bootTime2 := now.Add(-5 * time.Hour).Unix()
+	bootTime3 := now.Add(-2 * time.Hour).Unix()
+	withReason := func(event interpreter.Event, reason string) interpreter.Event {
+		event.Metadata[DefaultRebootReasonKey] = reason
+		return event
+	}
+
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime1, now.Add(-9*time.Hour), "a"),
+		testCycleEvent("2", "reboot-pending", bootTime1, now.Add(-6*time.Hour), "a"),
+		testCycleEvent("3", "offline", bootTime1, now.Add(-330*time.Minute), "a"),
+		withReason(testCycleEvent("4", "online", bootTime2, now.Add(-299*time.Minute), "b"), "firmware-upgrade"),
+		testCycleEvent("5", "offline", bootTime2, now.Add(-150*time.Minute), "b"),
+		withReason(testCycleEvent("6", "online", bootTime3, now.Add(-119*time.Minute), "c"), "scheduled"),
+	}
+
+	valid, err := RebootReasonValidator(RebootPolicy{}).Valid(events[:4])
+	assert.True(valid)
+	assert.Nil(err)
+
+	valid, err = RebootReasonValidator(RebootPolicy{}).Valid(events)
+	assert.False(valid)
+	assert.True(errors.Is(err, ErrRebootReasonMismatch))
+	var cvErr CycleValidationErr
+	assert.True(errors.As(err, &cvErr))
+	assert.Equal(validation.RebootReasonMismatch, cvErr.Tag())
+	assert.Equal([]string{fmt.Sprintf("%d: planned, reason scheduled, no reboot-pending", bootTime3)}, cvErr.Fields())
+
+	var mismatchErr RebootReasonMismatchErr
+	assert.True(errors.As(err, &mismatchErr))
+	assert.Len(mismatchErr.Mismatches, 1)
+	assert.Equal(ErrPlannedWithoutPending, mismatchErr.Mismatches[0].Mismatch)
+	assert.True(errors.Is(ErrPlannedWithoutPending, ErrRebootReasonMismatch))
+}
+
+func TestRebootTaxonomyLookup(t *testing.T) {
+	assert := assert.New(t)
+	taxonomy := DefaultRebootTaxonomy()
+	reason, found := taxonomy.Lookup(" Watchdog ")
+	assert.True(found)
+	assert.Equal(RebootReason{Category: "watchdog"}, reason)
+
+	_, found = taxonomy.Lookup("")
+	assert.False(found)
+	_, found = taxonomy.Lookup("unknown")
+	assert.False(found)
+}
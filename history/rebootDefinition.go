@@ -0,0 +1,147 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// RebootFallback decides which events are part of a reboot when none of the
+// event types marking the start or end of the reboot are found.
+type RebootFallback string
+
+const (
+	// FallbackNone leaves out all of the cycle's events. This is the default.
+	FallbackNone RebootFallback = "none"
+
+	// FallbackCycle includes all of the cycle's events.
+	FallbackCycle RebootFallback = "cycle"
+)
+
+// RebootDefinition describes which events mark the start and the end of a reboot.
+type RebootDefinition struct {
+	// Start are the event types that can start a reboot, in order of priority. The reboot starts at the latest
+	// event in the previous cycle with the first event type in the list that is found.
+	Start []string
+
+	// End are the event types that can end a reboot, in order of priority. The reboot ends at the first event
+	// in the new cycle with the first event type in the list that is found.
+	End []string
+
+	// StartFallback is used when none of the Start event types are found in the previous cycle.
+	StartFallback RebootFallback
+
+	// EndFallback is used when none of the End event types are found in the new cycle.
+	EndFallback RebootFallback
+}
+
+// DefaultRebootDefinition returns the definition of a reboot used when one is not given, which is the
+// RegistryRebootDefinition of interpreter.DefaultEventTypeRegistry. With the default event types, a reboot
+// starts at the last reboot-pending event, or else the last offline event, and ends at the first
+// fully-manageable event, or else the first operational event, or else the first online event.
+func DefaultRebootDefinition() RebootDefinition {
+	return RegistryRebootDefinition(interpreter.DefaultEventTypeRegistry())
+}
+
+// RegistryRebootDefinition returns a definition of a reboot based on the roles of the event types in the registry.
+// A reboot starts at an event type with the shutdown intent role, or else one with the session end role, and
+// ends at an event type with the boot milestone role. Within each role, event types expected later in a
+// boot cycle have a higher priority.
+func RegistryRebootDefinition(registry *interpreter.EventTypeRegistry) RebootDefinition {
+	return RebootDefinition{
+		Start:         append(reversed(registry.Names(interpreter.ShutdownIntentRole)), reversed(registry.Names(interpreter.SessionEndRole))...),
+		End:           reversed(registry.Names(interpreter.BootMilestoneRole)),
+		StartFallback: FallbackNone,
+		EndFallback:   FallbackNone,
+	}
+}
+
+func reversed(names []string) []string {
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return names
+}
+
+// startEvents takes in a list of events from the previous cycle and returns the events from the
+// start of the reboot onwards, sorted from newest to oldest by birthdate.
+func (d RebootDefinition) startEvents(events []interpreter.Event) []interpreter.Event {
+	if len(events) == 0 {
+		return events
+	}
+
+	sort.Slice(events, birthdateDescendingSortFunc(events))
+	if i := findByPriority(events, d.Start, false); i > -1 {
+		return events[:i+1]
+	}
+
+	return d.StartFallback.apply(events)
+}
+
+// endEvents takes in a list of events from the new cycle and returns the events up to and including
+// the end of the reboot, sorted from newest to oldest by birthdate.
+func (d RebootDefinition) endEvents(events []interpreter.Event) []interpreter.Event {
+	if len(events) == 0 {
+		return events
+	}
+
+	sort.Slice(events, birthdateDescendingSortFunc(events))
+	if i := findByPriority(events, d.End, true); i > -1 {
+		return events[i:]
+	}
+
+	return d.EndFallback.apply(events)
+}
+
+func (f RebootFallback) apply(events []interpreter.Event) []interpreter.Event {
+	if strings.EqualFold(string(f), string(FallbackCycle)) {
+		return events
+	}
+
+	return []interpreter.Event{}
+}
+
+// findByPriority returns the index of the event with the highest priority event type in a list of events
+// sorted from newest to oldest. If there are multiple events with that type, the newest one is returned,
+// or the oldest one if oldest is true. -1 is returned if none of the event types are found.
+func findByPriority(events []interpreter.Event, eventTypes []string, oldest bool) int {
+	found := make(map[string]int, len(eventTypes))
+	for i, event := range events {
+		eventType, err := event.EventType()
+		if err != nil {
+			continue
+		}
+
+		eventType = strings.ToLower(eventType)
+		if _, seen := found[eventType]; !seen || oldest {
+			found[eventType] = i
+		}
+	}
+
+	for _, eventType := range eventTypes {
+		if i, ok := found[strings.ToLower(strings.TrimSpace(eventType))]; ok {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,167 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestRebootDefinition(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	previousCycle := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+		testCycleEvent("2", "shutdown-requested", bootTime1, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("3", "offline", bootTime1, now.Add(-65*time.Minute), "a"),
+		testCycleEvent("4", "shutdown-requested", bootTime1, now.Add(-64*time.Minute), "a"),
+	}
+	currentCycle := []interpreter.Event{
+		testCycleEvent("5", "online", bootTime2, now.Add(-55*time.Minute), "b"),
+		testCycleEvent("6", "cloud-connected", bootTime2, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("7", "cloud-connected", bootTime2, now.Add(-45*time.Minute), "b"),
+		testCycleEvent("8", "operational", bootTime2, now.Add(-40*time.Minute), "b"),
+	}
+
+	tests := []struct {
+		description   string
+		definition    RebootDefinition
+		expectedStart []string
+		expectedEnd   []string
+	}{
+		{
+			description:   "default",
+			definition:    DefaultRebootDefinition(),
+			expectedStart: []string{"4", "3"},
+			expectedEnd:   []string{"8", "7", "6", "5"},
+		},
+		{
+			description: "custom event types",
+			definition: RebootDefinition{
+				Start: []string{"shutdown-requested", "offline"},
+				End:   []string{"Cloud-Connected", "operational"},
+			},
+			expectedStart: []string{"4"},
+			expectedEnd:   []string{"6", "5"},
+		},
+		{
+			description: "lower priority event type",
+			definition: RebootDefinition{
+				Start: []string{"reboot-pending", "offline"},
+				End:   []string{"fully-manageable", "online"},
+			},
+			expectedStart: []string{"4", "3"},
+			expectedEnd:   []string{"5"},
+		},
+		{
+			description:   "not found without fallback",
+			definition:    RebootDefinition{Start: []string{"reboot-pending"}, End: []string{"fully-manageable"}},
+			expectedStart: []string{},
+			expectedEnd:   []string{},
+		},
+		{
+			description: "not found with cycle fallback",
+			definition: RebootDefinition{
+				Start:         []string{"reboot-pending"},
+				End:           []string{"fully-manageable"},
+				StartFallback: FallbackCycle,
+				EndFallback:   FallbackCycle,
+			},
+			expectedStart: []string{"4", "3", "2", "1"},
+			expectedEnd:   []string{"8", "7", "6", "5"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(tc.expectedStart, testEventIDs(tc.definition.startEvents(copyEvents(previousCycle))))
+			assert.Equal(tc.expectedEnd, testEventIDs(tc.definition.endEvents(copyEvents(currentCycle))))
+			assert.Empty(tc.definition.startEvents(nil))
+			assert.Empty(tc.definition.endEvents(nil))
+
+			events := append(copyEvents(currentCycle), previousCycle...)
+			currentEvent := currentCycle[len(currentCycle)-1]
+			parsed, err := RebootParser(nil, WithRebootDefinition(tc.definition)).Parse(events, currentEvent)
+			assert.Nil(err)
+			assert.Equal(append(tc.expectedEnd, tc.expectedStart...), testEventIDs(parsed))
+
+			reboots := Reboots(events, WithRebootDefinition(tc.definition))
+			assert.Len(reboots, 1)
+			assert.Equal(append(tc.expectedEnd, tc.expectedStart...), testEventIDs(reboots[0].Events))
+		})
+	}
+}
+
+func TestRebootStartAndEnd(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+		testCycleEvent("2", "offline", bootTime1, now.Add(-65*time.Minute), "a"),
+		testCycleEvent("3", "online", bootTime2, now.Add(-55*time.Minute), "b"),
+		testCycleEvent("4", "operational", bootTime2, now.Add(-50*time.Minute), "b"),
+	}
+
+	reboot := Reboots(events)[0]
+	assert.Equal(now.Add(-65*time.Minute).UnixNano(), reboot.Start.UnixNano())
+	assert.Equal(now.Add(-50*time.Minute).UnixNano(), reboot.End.UnixNano())
+	duration, found := reboot.Duration()
+	assert.True(found)
+	assert.Equal(15*time.Minute, duration)
+
+	reboot = Reboots(events, WithRebootDefinition(RebootDefinition{End: []string{"online"}, StartFallback: FallbackCycle}))[0]
+	assert.True(reboot.Start.IsZero())
+	assert.Equal(now.Add(-55*time.Minute).UnixNano(), reboot.End.UnixNano())
+	_, found = reboot.Duration()
+	assert.False(found)
+}
+
+func TestRegistryRebootDefinition(t *testing.T) {
+	assert := assert.New(t)
+	registry := interpreter.NewEventTypeRegistry(
+		interpreter.EventTypeInfo{Name: "connected", Roles: interpreter.SessionStartRole | interpreter.BootMilestoneRole, Order: 1},
+		interpreter.EventTypeInfo{Name: "cloud-connected", Roles: interpreter.BootMilestoneRole, Order: 2},
+		interpreter.EventTypeInfo{Name: "shutdown-requested", Roles: interpreter.ShutdownIntentRole, Order: 3},
+		interpreter.EventTypeInfo{Name: "disconnected", Roles: interpreter.SessionEndRole, Order: 4},
+	)
+
+	definition := RegistryRebootDefinition(registry)
+	assert.Equal([]string{"shutdown-requested", "disconnected"}, definition.Start)
+	assert.Equal([]string{"cloud-connected", "connected"}, definition.End)
+	assert.Equal(FallbackNone, definition.StartFallback)
+	assert.Equal(FallbackNone, definition.EndFallback)
+
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("6", "cloud-connected", bootTime2, now.Add(-45*time.Minute), "b"),
+		testCycleEvent("5", "connected", bootTime2, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("4", "online", bootTime2, now.Add(-55*time.Minute), "b"),
+		testCycleEvent("3", "disconnected", bootTime1, now.Add(-65*time.Minute), "a"),
+		testCycleEvent("2", "offline", bootTime1, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("1", "connected", bootTime1, now.Add(-110*time.Minute), "a"),
+	}
+
+	reboots := Reboots(events, WithEventTypeRegistry(registry))
+	assert.Len(reboots, 1)
+	assert.Equal([]string{"6", "5", "4", "3"}, testEventIDs(reboots[0].Events))
+	assert.Equal(now.Add(-50*time.Minute).UnixNano(), reboots[0].FirstOnline.UnixNano())
+	assert.Equal(now.Add(-65*time.Minute).UnixNano(), reboots[0].LastOffline.UnixNano())
+	assert.Equal(now.Add(-45*time.Minute).UnixNano(), reboots[0].End.UnixNano())
+
+	sessions := Sessions(events, WithEventTypeRegistry(registry))
+	assert.Len(sessions, 2)
+	assert.Equal(SessionClosed, sessions[0].State())
+	online, found := sessions[1].Online()
+	assert.True(found)
+	assert.Equal("5", online.TransactionUUID)
+}
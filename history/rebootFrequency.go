@@ -0,0 +1,273 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrRebootStorm         = errors.New("too many reboots")
+	ErrConnectionFlapping  = errors.New("too many reconnects")
+	ErrInvalidRebootWindow = errors.New("invalid reboot frequency window")
+)
+
+// RebootFrequency is how often a device is allowed to reboot and reconnect.
+type RebootFrequency struct {
+	// Window is the length of time that reboots and reconnects are counted over.
+	Window time.Duration
+
+	// MaxReboots is the most reboots allowed within the window. A MaxReboots of 0 does not limit reboots.
+	MaxReboots int
+
+	// MaxReconnects is the most reconnects allowed within the window, where a reconnect is a session
+	// that starts with the same boot-time as the session before it. A MaxReconnects of 0 does not limit reconnects.
+	MaxReconnects int
+}
+
+// Validate returns ErrInvalidRebootWindow if reboots or reconnects are limited without a positive window,
+// since only reboots and reconnects at the exact same time would then be counted together.
+func (f RebootFrequency) Validate() error {
+	if (f.MaxReboots > 0 || f.MaxReconnects > 0) && f.Window <= 0 {
+		return fmt.Errorf("%w: %s must be positive when reboots or reconnects are limited", ErrInvalidRebootWindow, f.Window)
+	}
+
+	return nil
+}
+
+// RebootStorm is a period of time with more reboots than allowed.
+type RebootStorm struct {
+	Start time.Time
+	End   time.Time
+
+	// Reboots are the reboots within the period, sorted from oldest to newest.
+	Reboots []Reboot
+}
+
+// String returns the period of time along with the boot-times of the reboots.
+func (s RebootStorm) String() string {
+	bootTimes := make([]string, 0, len(s.Reboots))
+	for _, reboot := range s.Reboots {
+		bootTimes = append(bootTimes, fmt.Sprint(reboot.BootTime.Unix()))
+	}
+
+	return fmt.Sprintf("%s - %s: %d reboots (%s)", s.Start.UTC().Format(time.RFC3339), s.End.UTC().Format(time.RFC3339), len(s.Reboots), strings.Join(bootTimes, ", "))
+}
+
+// ConnectionFlap is a period of time with more reconnects than allowed.
+type ConnectionFlap struct {
+	Start time.Time
+	End   time.Time
+
+	// Sessions are the sessions that reconnected within the period, sorted from oldest to newest.
+	Sessions []Session
+}
+
+// String returns the period of time along with the ids of the sessions.
+func (f ConnectionFlap) String() string {
+	ids := make([]string, 0, len(f.Sessions))
+	for _, session := range f.Sessions {
+		ids = append(ids, session.ID)
+	}
+
+	return fmt.Sprintf("%s - %s: %d reconnects (%s)", f.Start.UTC().Format(time.RFC3339), f.End.UTC().Format(time.RFC3339), len(f.Sessions), strings.Join(ids, ", "))
+}
+
+// RebootStormErr is returned by RebootFrequencyValidator and contains every reboot storm found.
+type RebootStormErr struct {
+	Storms []RebootStorm
+}
+
+func (e RebootStormErr) Error() string {
+	return fmt.Sprintf("%v: %d windows found", ErrRebootStorm, len(e.Storms))
+}
+
+func (e RebootStormErr) Unwrap() error {
+	return ErrRebootStorm
+}
+
+// Tag implements the TaggedError interface.
+func (e RebootStormErr) Tag() validation.Tag {
+	return validation.RebootStorm
+}
+
+// ConnectionFlappingErr is returned by RebootFrequencyValidator and contains every period of connection flapping found.
+type ConnectionFlappingErr struct {
+	Flaps []ConnectionFlap
+}
+
+func (e ConnectionFlappingErr) Error() string {
+	return fmt.Sprintf("%v: %d windows found", ErrConnectionFlapping, len(e.Flaps))
+}
+
+func (e ConnectionFlappingErr) Unwrap() error {
+	return ErrConnectionFlapping
+}
+
+// Tag implements the TaggedError interface.
+func (e ConnectionFlappingErr) Tag() validation.Tag {
+	return validation.ConnectionFlapping
+}
+
+// RebootFrequencyValidator returns a CycleValidatorFunc that validates that a device does not reboot or reconnect
+// more often than allowed. Reboots are found with Reboots and counted by their boot-times, while reconnects are found
+// with Sessions and counted by the birthdates of their first events. Wherever there are more reboots or reconnects
+// within the window than allowed, the overlapping windows are merged and reported. If both reboot storms and connection
+// flapping are found, a validation.Errors containing both errors is returned. It is meant to be run on a device's whole history.
+func RebootFrequencyValidator(frequency RebootFrequency, opts ...Option) CycleValidatorFunc {
+	return func(events []interpreter.Event) (bool, error) {
+		var errs validation.Errors
+		if storms := findRebootStorms(events, frequency, opts); len(storms) > 0 {
+			details := make([]string, 0, len(storms))
+			var offending []interpreter.Event
+			for _, storm := range storms {
+				details = append(details, storm.String())
+				for _, reboot := range storm.Reboots {
+					offending = append(offending, reboot.Events...)
+				}
+			}
+
+			errs = append(errs, CycleValidationErr{
+				OriginalErr:       RebootStormErr{Storms: storms},
+				ErrorDetailKey:    "reboot storms",
+				ErrorDetailValues: details,
+				ErrorTag:          validation.RebootStorm,
+				OffendingEvents:   uniqueEvents(offending),
+			})
+		}
+
+		if flaps := findConnectionFlaps(events, frequency, opts); len(flaps) > 0 {
+			details := make([]string, 0, len(flaps))
+			var offending []interpreter.Event
+			for _, flap := range flaps {
+				details = append(details, flap.String())
+				for _, session := range flap.Sessions {
+					offending = append(offending, session.Events...)
+				}
+			}
+
+			errs = append(errs, CycleValidationErr{
+				OriginalErr:       ConnectionFlappingErr{Flaps: flaps},
+				ErrorDetailKey:    "connection flapping",
+				ErrorDetailValues: details,
+				ErrorTag:          validation.ConnectionFlapping,
+				OffendingEvents:   uniqueEvents(offending),
+			})
+		}
+
+		switch len(errs) {
+		case 0:
+			return true, nil
+		case 1:
+			return false, errs[0]
+		default:
+			return false, errs
+		}
+	}
+}
+
+func findRebootStorms(events []interpreter.Event, frequency RebootFrequency, opts []Option) []RebootStorm {
+	if frequency.MaxReboots <= 0 {
+		return nil
+	}
+
+	reboots := Reboots(events, opts...)
+	// reboots are sorted from newest to oldest, so reverse them
+	for i, j := 0, len(reboots)-1; i < j; i, j = i+1, j-1 {
+		reboots[i], reboots[j] = reboots[j], reboots[i]
+	}
+
+	times := make([]time.Time, 0, len(reboots))
+	for _, reboot := range reboots {
+		times = append(times, reboot.BootTime)
+	}
+
+	var storms []RebootStorm
+	for _, window := range findFrequentWindows(times, frequency.Window, frequency.MaxReboots) {
+		storms = append(storms, RebootStorm{
+			Start:   times[window[0]],
+			End:     times[window[1]],
+			Reboots: reboots[window[0] : window[1]+1],
+		})
+	}
+
+	return storms
+}
+
+func findConnectionFlaps(events []interpreter.Event, frequency RebootFrequency, opts []Option) []ConnectionFlap {
+	if frequency.MaxReconnects <= 0 {
+		return nil
+	}
+
+	clusters := newOptions(opts).clusterBootTimes(events)
+	sessions := Sessions(events, opts...)
+	var reconnects []Session
+	var times []time.Time
+	for i := 1; i < len(sessions); i++ {
+		if sessions[i].BootTime > 0 && clusters.Same(sessions[i].BootTime, sessions[i-1].BootTime) {
+			reconnects = append(reconnects, sessions[i])
+			times = append(times, time.Unix(0, sessions[i].Events[0].Birthdate))
+		}
+	}
+
+	var flaps []ConnectionFlap
+	for _, window := range findFrequentWindows(times, frequency.Window, frequency.MaxReconnects) {
+		flaps = append(flaps, ConnectionFlap{
+			Start:    times[window[0]],
+			End:      times[window[1]],
+			Sessions: reconnects[window[0] : window[1]+1],
+		})
+	}
+
+	return flaps
+}
+
+// findFrequentWindows takes in times sorted from oldest to newest and returns the first and last indexes
+// of each period where there are more than max times within the window of each other. Overlapping
+// periods are merged.
+func findFrequentWindows(times []time.Time, window time.Duration, max int) [][2]int {
+	var windows [][2]int
+	end := 0
+	for start := range times {
+		if end < start {
+			end = start
+		}
+
+		for end+1 < len(times) && times[end+1].Sub(times[start]) <= window {
+			end++
+		}
+
+		if end-start+1 <= max {
+			continue
+		}
+
+		if last := len(windows) - 1; last >= 0 && start <= windows[last][1] {
+			windows[last][1] = end
+		} else {
+			windows = append(windows, [2]int{start, end})
+		}
+	}
+
+	return windows
+}
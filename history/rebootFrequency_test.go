@@ -0,0 +1,234 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestRebootFrequencyValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+
+	// one boot cycle every 10 minutes, with each cycle having an online event
+	rebootEvents := func(start time.Time, count int) []interpreter.Event {
+		var events []interpreter.Event
+		for i := 0; i < count; i++ {
+			bootTime := start.Add(time.Duration(i) * 10 * time.Minute)
+			events = append(events, testCycleEvent(fmt.Sprintf("r%d", i), "online", bootTime.Unix(), bootTime.Add(time.Minute), fmt.Sprintf("r%d", i)))
+		}
+		return events
+	}
+
+	// sessions every 5 minutes within the same boot cycle
+	reconnectEvents := func(bootTime time.Time, count int) []interpreter.Event {
+		var events []interpreter.Event
+		for i := 0; i < count; i++ {
+			events = append(events, testCycleEvent(fmt.Sprintf("s%d", i), "online", bootTime.Unix(), bootTime.Add(time.Duration(i+1)*5*time.Minute), fmt.Sprintf("s%d", i)))
+		}
+		return events
+	}
+
+	// the second burst also counts the reboot from the last cycle of the first burst
+	bursty := append(rebootEvents(now.Add(-10*time.Hour), 4), rebootEvents(now.Add(-5*time.Hour), 5)...)
+	tests := []struct {
+		description            string
+		frequency              RebootFrequency
+		events                 []interpreter.Event
+		expectedStorms         []int
+		expectedFlaps          []int
+		expectedStormBootTimes []int64
+	}{
+		{
+			description: "no limits",
+			events:      rebootEvents(now.Add(-5*time.Hour), 10),
+		},
+		{
+			description: "empty",
+			frequency:   RebootFrequency{Window: time.Hour, MaxReboots: 1, MaxReconnects: 1},
+		},
+		{
+			description: "within limits",
+			frequency:   RebootFrequency{Window: time.Hour, MaxReboots: 7},
+			events:      rebootEvents(now.Add(-5*time.Hour), 10),
+		},
+		{
+			description:    "reboot storm",
+			frequency:      RebootFrequency{Window: 30 * time.Minute, MaxReboots: 2},
+			events:         rebootEvents(now.Add(-5*time.Hour), 5),
+			expectedStorms: []int{4},
+			expectedStormBootTimes: []int64{
+				now.Add(-5*time.Hour + 10*time.Minute).Unix(),
+				now.Add(-5*time.Hour + 20*time.Minute).Unix(),
+				now.Add(-5*time.Hour + 30*time.Minute).Unix(),
+				now.Add(-5*time.Hour + 40*time.Minute).Unix(),
+			},
+		},
+		{
+			description:    "separate reboot storms",
+			frequency:      RebootFrequency{Window: 30 * time.Minute, MaxReboots: 2},
+			events:         bursty,
+			expectedStorms: []int{3, 5},
+		},
+		{
+			description:   "connection flapping",
+			frequency:     RebootFrequency{Window: 15 * time.Minute, MaxReboots: 1, MaxReconnects: 3},
+			events:        reconnectEvents(now.Add(-5*time.Hour), 6),
+			expectedFlaps: []int{5},
+		},
+		{
+			description:    "reboot storm and connection flapping",
+			frequency:      RebootFrequency{Window: 30 * time.Minute, MaxReboots: 2, MaxReconnects: 3},
+			events:         append(rebootEvents(now.Add(-5*time.Hour), 4), reconnectEvents(now.Add(-2*time.Hour), 5)...),
+			expectedStorms: []int{3},
+			expectedFlaps:  []int{4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			valid, err := RebootFrequencyValidator(tc.frequency).Valid(tc.events)
+			if len(tc.expectedStorms) == 0 && len(tc.expectedFlaps) == 0 {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			var errs validation.Errors
+			if !errors.As(err, &errs) {
+				errs = validation.Errors{err}
+			}
+
+			var stormErr RebootStormErr
+			var flapErr ConnectionFlappingErr
+			var tags []validation.Tag
+			for _, e := range errs {
+				var cvErr CycleValidationErr
+				assert.True(errors.As(e, &cvErr))
+				tags = append(tags, cvErr.Tag())
+				errors.As(e, &stormErr)
+				errors.As(e, &flapErr)
+			}
+
+			var expectedTags []validation.Tag
+			if len(tc.expectedStorms) > 0 {
+				expectedTags = append(expectedTags, validation.RebootStorm)
+			}
+			if len(tc.expectedFlaps) > 0 {
+				expectedTags = append(expectedTags, validation.ConnectionFlapping)
+			}
+			assert.Equal(expectedTags, tags)
+
+			var stormSizes []int
+			for _, storm := range stormErr.Storms {
+				stormSizes = append(stormSizes, len(storm.Reboots))
+				assert.Equal(storm.Reboots[0].BootTime, storm.Start)
+				assert.Equal(storm.Reboots[len(storm.Reboots)-1].BootTime, storm.End)
+			}
+			assert.Equal(tc.expectedStorms, stormSizes)
+
+			if len(tc.expectedStormBootTimes) > 0 {
+				var bootTimes []int64
+				for _, reboot := range stormErr.Storms[0].Reboots {
+					bootTimes = append(bootTimes, reboot.BootTime.Unix())
+				}
+				assert.Equal(tc.expectedStormBootTimes, bootTimes)
+			}
+
+			var flapSizes []int
+			for _, flap := range flapErr.Flaps {
+				flapSizes = append(flapSizes, len(flap.Sessions))
+			}
+			assert.Equal(tc.expectedFlaps, flapSizes)
+		})
+	}
+}
+
+func TestRebootFrequencyValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		frequency   RebootFrequency
+		expectedErr error
+	}{
+		{
+			description: "no limits",
+		},
+		{
+			description: "limits with window",
+			frequency:   RebootFrequency{Window: time.Hour, MaxReboots: 3, MaxReconnects: 5},
+		},
+		{
+			description: "reboot limit without window",
+			frequency:   RebootFrequency{MaxReboots: 3},
+			expectedErr: ErrInvalidRebootWindow,
+		},
+		{
+			description: "reconnect limit with negative window",
+			frequency:   RebootFrequency{Window: -time.Hour, MaxReconnects: 5},
+			expectedErr: ErrInvalidRebootWindow,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.frequency.Validate()
+			assert.True(t, errors.Is(err, tc.expectedErr))
+			if tc.expectedErr == nil {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestFindFrequentWindows(t *testing.T) {
+	start := time.Now()
+	times := func(minutes ...int) []time.Time {
+		var times []time.Time
+		for _, m := range minutes {
+			times = append(times, start.Add(time.Duration(m)*time.Minute))
+		}
+		return times
+	}
+
+	tests := []struct {
+		description string
+		times       []time.Time
+		expected    [][2]int
+	}{
+		{
+			description: "empty",
+		},
+		{
+			description: "not enough",
+			times:       times(0, 10, 20, 30),
+		},
+		{
+			description: "one window",
+			times:       times(0, 5, 10, 40),
+			expected:    [][2]int{{0, 2}},
+		},
+		{
+			description: "merged windows",
+			times:       times(0, 5, 10, 15, 20, 60),
+			expected:    [][2]int{{0, 4}},
+		},
+		{
+			description: "separate windows",
+			times:       times(0, 5, 10, 60, 61, 62),
+			expected:    [][2]int{{0, 2}, {3, 5}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, findFrequentWindows(tc.times, 10*time.Minute, 2))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrRebootScheduleDeviation = errors.New("reboot did not happen as scheduled")
+	ErrRebootTooEarly          = fmt.Errorf("%w: reboot happened too early", ErrRebootScheduleDeviation)
+	ErrRebootTooLate           = fmt.Errorf("%w: reboot happened too late", ErrRebootScheduleDeviation)
+	ErrRebootMissed            = fmt.Errorf("%w: reboot never happened", ErrRebootScheduleDeviation)
+)
+
+// RebootScheduleDeviation is a reboot-pending event whose reboot did not happen within the tolerance of the scheduled time.
+type RebootScheduleDeviation struct {
+	RebootPending interpreter.Event
+
+	// Scheduled is the time the device was scheduled to reboot, as parsed by interpreter.Event.RebootSchedule.
+	Scheduled time.Time
+
+	// Offline is the birthdate of the first offline event after the reboot-pending event in the same cycle, and
+	// BootTime is the boot-time of the next cycle. Each is left as the zero time if it was not found.
+	Offline  time.Time
+	BootTime time.Time
+
+	// Err is ErrRebootTooEarly, ErrRebootTooLate or ErrRebootMissed.
+	Err error
+}
+
+// String returns the reboot-pending event's transaction uuid along with the scheduled and actual times.
+func (d RebootScheduleDeviation) String() string {
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return "none"
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("%s: %v (scheduled %s, offline %s, boot-time %s)", d.RebootPending.TransactionUUID, d.Err, format(d.Scheduled), format(d.Offline), format(d.BootTime))
+}
+
+// RebootScheduleErr is returned by RebootScheduleValidator and contains every reboot that did not happen as scheduled.
+type RebootScheduleErr struct {
+	Deviations []RebootScheduleDeviation
+}
+
+func (e RebootScheduleErr) Error() string {
+	return fmt.Sprintf("%v: %d reboots found", ErrRebootScheduleDeviation, len(e.Deviations))
+}
+
+func (e RebootScheduleErr) Unwrap() error {
+	return ErrRebootScheduleDeviation
+}
+
+// Tag implements the TaggedError interface.
+func (e RebootScheduleErr) Tag() validation.Tag {
+	return validation.RebootScheduleDeviation
+}
+
+// RebootScheduleValidator returns a CycleValidatorFunc that validates that devices reboot when their reboot-pending events
+// say they will. For the last reboot-pending event of each cycle with a schedule in its destination, the first offline event
+// after it in the same cycle and the boot-time of the next cycle must be within the tolerance of the scheduled time.
+// A reboot is reported as missed if there is no next cycle even though the history goes on past the scheduled time
+// and the tolerance. Reboot-pending and offline events are the event types with the shutdown intent and session end
+// roles in the event type registry. It is meant to be run on a device's whole history.
+func RebootScheduleValidator(tolerance time.Duration, opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
+	return func(events []interpreter.Event) (bool, error) {
+		var latest int64
+		for _, event := range events {
+			if event.Birthdate > latest {
+				latest = event.Birthdate
+			}
+		}
+
+		cycles := SplitIntoCycles(events, opts...)
+		var deviations []RebootScheduleDeviation
+		// cycles are sorted from newest to oldest, so go backwards to report the oldest reboots first
+		for i := len(cycles) - 1; i >= 0; i-- {
+			var nextBootTime time.Time
+			if i > 0 {
+				nextBootTime = time.Unix(cycles[i-1].BootTime, 0)
+			}
+
+			if deviation, found := checkRebootSchedule(cycles[i], nextBootTime, time.Unix(0, latest), tolerance, o); found {
+				deviations = append(deviations, deviation)
+			}
+		}
+
+		if len(deviations) == 0 {
+			return true, nil
+		}
+
+		details := make([]string, 0, len(deviations))
+		offending := make([]interpreter.Event, 0, len(deviations))
+		for _, deviation := range deviations {
+			details = append(details, deviation.String())
+			offending = append(offending, deviation.RebootPending)
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       RebootScheduleErr{Deviations: deviations},
+			ErrorDetailKey:    "reboot schedule deviations",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.RebootScheduleDeviation,
+			OffendingEvents:   offending,
+		}
+	}
+}
+
+// checkRebootSchedule compares the schedule of the last reboot-pending event in the cycle with the cycle's first offline
+// event after it and the next cycle's boot-time. True is returned if the reboot did not happen as scheduled.
+func checkRebootSchedule(cycle Cycle, nextBootTime time.Time, latest time.Time, tolerance time.Duration, o options) (RebootScheduleDeviation, bool) {
+	pendingIndex := -1
+	var scheduled time.Time
+	for i := len(cycle.events) - 1; i >= 0; i-- {
+		if !o.hasRole(cycle.events[i], interpreter.ShutdownIntentRole) {
+			continue
+		}
+
+		if timestamp, delay, err := cycle.events[i].RebootSchedule(); err == nil {
+			pendingIndex = i
+			scheduled = timestamp.Add(delay)
+			break
+		}
+	}
+
+	if pendingIndex < 0 {
+		return RebootScheduleDeviation{}, false
+	}
+
+	deviation := RebootScheduleDeviation{
+		RebootPending: cycle.events[pendingIndex],
+		Scheduled:     scheduled,
+		BootTime:      nextBootTime,
+	}
+
+	for _, event := range cycle.events[pendingIndex+1:] {
+		if o.hasRole(event, interpreter.SessionEndRole) {
+			deviation.Offline = time.Unix(0, event.Birthdate)
+			break
+		}
+	}
+
+	earliest, deadline := scheduled.Add(-tolerance), scheduled.Add(tolerance)
+	before := func(t time.Time) bool { return !t.IsZero() && t.Before(earliest) }
+	after := func(t time.Time) bool { return !t.IsZero() && t.After(deadline) }
+	switch {
+	case before(deviation.Offline) || before(deviation.BootTime):
+		deviation.Err = ErrRebootTooEarly
+	case after(deviation.Offline) || after(deviation.BootTime):
+		deviation.Err = ErrRebootTooLate
+	case deviation.BootTime.IsZero() && latest.After(deadline):
+		deviation.Err = ErrRebootMissed
+	default:
+		return RebootScheduleDeviation{}, false
+	}
+
+	return deviation, true
+}
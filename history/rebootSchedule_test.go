@@ -0,0 +1,119 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestRebootScheduleValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-10 * time.Hour).Unix()
+	scheduledAt := now.Add(-6 * time.Hour)
+	scheduled := scheduledAt.Add(10 * time.Minute)
+	pending := fmt.Sprintf("reboot-pending/%d/10m", scheduledAt.Unix())
+	onTime := scheduled.Add(2 * time.Minute).Unix()
+	tests := []struct {
+		description string
+		events      []interpreter.Event
+		expectedErr error
+	}{
+		{
+			description: "on time",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", pending, bootTime, scheduledAt, "a"),
+				testCycleEvent("3", "offline", bootTime, scheduled.Add(-time.Minute), "a"),
+				testCycleEvent("4", "online", onTime, scheduled.Add(3*time.Minute), "b"),
+			},
+		},
+		{
+			description: "offline too early",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", pending, bootTime, scheduledAt, "a"),
+				testCycleEvent("3", "offline", bootTime, scheduled.Add(-8*time.Minute), "a"),
+				testCycleEvent("4", "online", onTime, scheduled.Add(3*time.Minute), "b"),
+			},
+			expectedErr: ErrRebootTooEarly,
+		},
+		{
+			description: "boot-time too late",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", pending, bootTime, scheduledAt, "a"),
+				testCycleEvent("3", "offline", bootTime, scheduled.Add(-time.Minute), "a"),
+				testCycleEvent("4", "online", scheduled.Add(30*time.Minute).Unix(), scheduled.Add(31*time.Minute), "b"),
+			},
+			expectedErr: ErrRebootTooLate,
+		},
+		{
+			description: "missed",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", pending, bootTime, scheduledAt, "a"),
+				testCycleEvent("3", "operational", bootTime, scheduled.Add(time.Hour), "a"),
+			},
+			expectedErr: ErrRebootMissed,
+		},
+		{
+			description: "history ends",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", pending, bootTime, scheduledAt, "a"),
+				testCycleEvent("3", "offline", bootTime, scheduled.Add(time.Minute), "a"),
+			},
+		},
+		{
+			description: "no schedule",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", "reboot-pending", bootTime, scheduledAt, "a"),
+				testCycleEvent("3", "online", now.Add(-time.Hour).Unix(), now.Add(-59*time.Minute), "b"),
+			},
+		},
+		{
+			description: "rescheduled",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-9*time.Hour), "a"),
+				testCycleEvent("2", fmt.Sprintf("reboot-pending/%d/1m", now.Add(-8*time.Hour).Unix()), bootTime, now.Add(-8*time.Hour), "a"),
+				testCycleEvent("3", pending, bootTime, scheduledAt, "a"),
+				testCycleEvent("4", "online", onTime, scheduled.Add(3*time.Minute), "b"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			valid, err := RebootScheduleValidator(5 * time.Minute).Valid(tc.events)
+			if tc.expectedErr == nil {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			assert.True(errors.Is(err, ErrRebootScheduleDeviation))
+			var cvErr CycleValidationErr
+			assert.True(errors.As(err, &cvErr))
+			assert.Equal(validation.RebootScheduleDeviation, cvErr.Tag())
+			assert.Len(cvErr.Fields(), 1)
+
+			var scheduleErr RebootScheduleErr
+			assert.True(errors.As(err, &scheduleErr))
+			if assert.Len(scheduleErr.Deviations, 1) {
+				deviation := scheduleErr.Deviations[0]
+				assert.Equal(tc.expectedErr, deviation.Err)
+				assert.Equal("2", deviation.RebootPending.TransactionUUID)
+				assert.True(scheduled.Equal(deviation.Scheduled))
+			}
+		})
+	}
+}
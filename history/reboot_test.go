@@ -0,0 +1,69 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestReboots(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime1 := now.Add(-3 * time.Hour)
+	bootTime2 := now.Add(-2 * time.Hour)
+	bootTime3 := now.Add(-1 * time.Hour)
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime1.Unix(), bootTime1.Add(time.Minute), "a"),
+		testCycleEvent("2", "reboot-pending", bootTime1.Unix(), bootTime2.Add(-3*time.Minute), "a"),
+		testCycleEvent("3", "offline", bootTime1.Unix(), bootTime2.Add(-2*time.Minute), "a"),
+		testCycleEvent("4", "online", bootTime2.Unix(), bootTime2.Add(time.Minute), "b"),
+		testCycleEvent("5", "operational", bootTime2.Unix(), bootTime2.Add(2*time.Minute), "b"),
+		testCycleEvent("6", "fully-manageable", bootTime2.Unix(), bootTime2.Add(3*time.Minute), "b"),
+		testCycleEvent("7", "some-event", bootTime2.Unix(), bootTime2.Add(4*time.Minute), "b"),
+		testCycleEvent("8", "online", bootTime3.Unix(), bootTime3.Add(time.Minute), "c"),
+	}
+
+	reboots := Reboots(events)
+	assert.Len(reboots, 2)
+
+	latest := reboots[0]
+	assert.Equal(bootTime3.Unix(), latest.BootTime.Unix())
+	assert.Equal(bootTime2.Unix(), latest.PreviousBootTime.Unix())
+	assert.True(latest.RebootPending.IsZero())
+	assert.True(latest.LastOffline.IsZero())
+	assert.Equal(bootTime3.Add(time.Minute).UnixNano(), latest.FirstOnline.UnixNano())
+	assert.Equal([]string{"8"}, testEventIDs(latest.Events))
+	_, found := latest.OfflineDuration()
+	assert.False(found)
+	_, found = latest.BootToOperational()
+	assert.False(found)
+
+	reboot := reboots[1]
+	assert.Equal(bootTime2.Unix(), reboot.BootTime.Unix())
+	assert.Equal(bootTime1.Unix(), reboot.PreviousBootTime.Unix())
+	assert.Equal([]string{"6", "5", "4", "3", "2"}, testEventIDs(reboot.Events))
+	assert.False(reboot.Scheduled)
+
+	durations := []struct {
+		durationFunc func() (time.Duration, bool)
+		expected     time.Duration
+	}{
+		{durationFunc: reboot.ShutdownDuration, expected: time.Minute},
+		{durationFunc: reboot.OfflineDuration, expected: 3 * time.Minute},
+		{durationFunc: reboot.BootToOnline, expected: time.Minute},
+		{durationFunc: reboot.BootToOperational, expected: 2 * time.Minute},
+		{durationFunc: reboot.BootToFullyManageable, expected: 3 * time.Minute},
+	}
+
+	for _, d := range durations {
+		duration, found := d.durationFunc()
+		assert.True(found)
+		assert.Equal(d.expected, duration)
+	}
+
+	assert.Empty(Reboots(events[:3]))
+	assert.Empty(Reboots(nil))
+}
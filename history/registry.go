@@ -0,0 +1,581 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+// CycleValidatorFactory creates a CycleValidator from its configuration.
+type CycleValidatorFactory func(decode validation.ConfigDecoder) (CycleValidator, error)
+
+type registryEntry struct {
+	descriptor validation.Descriptor
+	factory    CycleValidatorFactory
+}
+
+// Registry holds named CycleValidator factories along with their descriptions.
+type Registry struct {
+	lock    sync.RWMutex
+	entries map[string]registryEntry
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]registryEntry),
+	}
+}
+
+// Register adds a cycle validator to the registry. The descriptor's name must be
+// non-empty and unique within the registry.
+func (r *Registry) Register(descriptor validation.Descriptor, factory CycleValidatorFactory) error {
+	if len(descriptor.Name) == 0 {
+		return validation.ErrEmptyValidatorName
+	}
+
+	if factory == nil {
+		return validation.ErrNilFactory
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, found := r.entries[descriptor.Name]; found {
+		return fmt.Errorf("%w: %s", validation.ErrDuplicateValidatorName, descriptor.Name)
+	}
+
+	r.entries[descriptor.Name] = registryEntry{descriptor: descriptor, factory: factory}
+	return nil
+}
+
+// New creates the cycle validator registered under name, using decode to read its configuration.
+func (r *Registry) New(name string, decode validation.ConfigDecoder) (CycleValidator, error) {
+	r.lock.RLock()
+	entry, found := r.entries[name]
+	r.lock.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("%w: %s", validation.ErrValidatorNotFound, name)
+	}
+
+	return entry.factory(decode)
+}
+
+// Descriptor returns the descriptor of the cycle validator registered under name.
+func (r *Registry) Descriptor(name string) (validation.Descriptor, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	entry, found := r.entries[name]
+	return entry.descriptor, found
+}
+
+// Descriptors returns the descriptors of all registered cycle validators, sorted by name.
+func (r *Registry) Descriptors() []validation.Descriptor {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	descriptors := make([]validation.Descriptor, 0, len(r.entries))
+	for _, entry := range r.entries {
+		descriptors = append(descriptors, entry.descriptor)
+	}
+
+	sort.Slice(descriptors, func(a, b int) bool {
+		return descriptors[a].Name < descriptors[b].Name
+	})
+
+	return descriptors
+}
+
+var defaultRegistry = newDefaultRegistry()
+
+// DefaultRegistry returns the registry that contains all of the built-in cycle validators.
+// Cycle validators registered with Register are added to this registry.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a cycle validator to the default registry.
+func Register(descriptor validation.Descriptor, factory CycleValidatorFactory) error {
+	return defaultRegistry.Register(descriptor, factory)
+}
+
+func newDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, entry := range builtInCycleValidators {
+		// built-in names are unique, so registration cannot fail
+		registry.Register(entry.descriptor, entry.factory)
+	}
+
+	return registry
+}
+
+// builtInCycleValidators are the cycle validators found in the default registry.
+var builtInCycleValidators = []registryEntry{
+	{
+		descriptor: validation.Descriptor{
+			Name:        "metadata",
+			Description: "validates that events have the same values for the configured metadata keys",
+			Tags:        []validation.Tag{validation.InconsistentMetadata},
+			Config: []validation.ConfigField{
+				{Name: "keys", Type: "[]string", Description: "metadata keys to check"},
+				{Name: "checkWithinCycle", Type: "bool", Description: "only compare events with the same boot-time"},
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				Keys              []string
+				CheckWithinCycle  bool
+				BootTimeTolerance time.Duration
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return MetadataValidator(config.Keys, config.CheckWithinCycle, WithBootTimeTolerance(config.BootTimeTolerance)), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "transaction_uuid",
+			Description: "validates that no two events share a transaction uuid",
+			Tags:        []validation.Tag{validation.RepeatedTransactionUUID},
+		},
+		factory: func(_ validation.ConfigDecoder) (CycleValidator, error) {
+			return TransactionUUIDValidator(), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "session_online",
+			Description: "validates that every session has an online event",
+			Tags:        []validation.Tag{validation.MissingOnlineEvent},
+		},
+		factory: func(_ validation.ConfigDecoder) (CycleValidator, error) {
+			return SessionOnlineValidator(nil), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "session_offline",
+			Description: "validates that every session has an offline event",
+			Tags:        []validation.Tag{validation.MissingOfflineEvent},
+		},
+		factory: func(_ validation.ConfigDecoder) (CycleValidator, error) {
+			return SessionOfflineValidator(nil), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "session_boot_time",
+			Description: "validates that the events of every session share a boot-time",
+			Tags:        []validation.Tag{validation.SessionBootTimeMismatch},
+			Config: []validation.ConfigField{
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct{ BootTimeTolerance time.Duration }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return SessionBootTimeValidator(nil, WithBootTimeTolerance(config.BootTimeTolerance)), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:         "session_overlap",
+			Description:  "validates that sessions do not overlap in time",
+			Tags:         []validation.Tag{validation.OverlappingSessions},
+			WholeHistory: true,
+		},
+		factory: func(_ validation.ConfigDecoder) (CycleValidator, error) {
+			return SessionOverlapValidator(nil), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:         "session_event_order",
+			Description:  "validates that no session has an offline event before its online event",
+			Tags:         []validation.Tag{validation.OfflineBeforeOnline},
+			WholeHistory: true,
+		},
+		factory: func(_ validation.ConfigDecoder) (CycleValidator, error) {
+			return SessionEventOrderValidator(nil), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:         "session_start",
+			Description:  "validates that every session starts after the previous session ended",
+			Tags:         []validation.Tag{validation.SessionStartedWhileOpen},
+			WholeHistory: true,
+		},
+		factory: func(_ validation.ConfigDecoder) (CycleValidator, error) {
+			return SessionStartValidator(nil), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "event_order",
+			Description: "validates that the configured event types appear in order",
+			Tags:        []validation.Tag{validation.InvalidEventOrder},
+			Config: []validation.ConfigField{
+				{Name: "order", Type: "[]string", Description: "event types in the order they must appear"},
+			},
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct{ Order []string }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return EventOrderValidator(config.Order), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "sequence",
+			Description: "validates that consecutive events match the configured sequence pattern",
+			Tags:        []validation.Tag{validation.InvalidEventOrder},
+			Config: []validation.ConfigField{
+				{Name: "pattern", Type: "string", Description: "sequence pattern, such as \"online operational? .* offline<5m\""},
+			},
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct{ Pattern string }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+
+			pattern, err := ParseSequencePattern(config.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			return SequenceValidator(pattern), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "true_reboot",
+			Description: "validates that the latest online event of the history follows an event with a different boot-time, meaning that the device last connected after a reboot",
+			Tags:        []validation.Tag{validation.FalseReboot, validation.NoReboot},
+			Config: []validation.ConfigField{
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct{ BootTimeTolerance time.Duration }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return TrueRebootValidator(WithBootTimeTolerance(config.BootTimeTolerance)), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "state_machine",
+			Description: "validates that every event type is allowed to follow the event type before it",
+			Tags:        []validation.Tag{validation.IllegalStateTransition},
+			Config: []validation.ConfigField{
+				{Name: "transitions", Type: "map[string][]string", Description: "event types allowed to follow each event type; defaults to the device lifecycle"},
+			},
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct{ Transitions map[string][]string }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return StateMachineValidator(config.Transitions), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "gap",
+			Description: "validates that events are not too far apart within a boot cycle",
+			Tags:        []validation.Tag{validation.EventGap},
+			Config: []validation.ConfigField{
+				{Name: "maxGap", Type: "duration", Description: "longest time allowed between consecutive events; 0 for no limit"},
+				{Name: "limits", Type: "[]GapLimit", Description: "longest time allowed from an event type (from) to the next event of another event type (to), as maxGap"},
+			},
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				MaxGap time.Duration
+				Limits []GapLimit
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return GapValidator(config.MaxGap, config.Limits), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "near_duplicate",
+			Description: "validates that no two events with the same content or type are within a time window of each other",
+			Tags:        []validation.Tag{validation.DuplicateEvent},
+			Config: []validation.ConfigField{
+				{Name: "window", Type: "duration", Description: "how close together events must be to be duplicates"},
+				{Name: "by", Type: "string", Description: "\"content\" to compare event fingerprints or \"type\" to compare event types; defaults to content"},
+				{Name: "ignoredFields", Type: "[]string", Description: "event fields left out of the fingerprint in addition to the birthdate and session id, such as \"metadata:/trust\""},
+			},
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				Window        time.Duration
+				By            string
+				IgnoredFields []string
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+
+			key, err := duplicateKey(config.By, config.IgnoredFields)
+			if err != nil {
+				return nil, err
+			}
+			return NearDuplicateValidator(config.Window, key), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:         "boot_time_regression",
+			Description:  "validates that boot-times never go backwards across the history",
+			Tags:         []validation.Tag{validation.BootTimeRegression},
+			Config:       bootTimeHistoryConfig,
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			opts, err := decodeBootTimeHistoryConfig(decode)
+			if err != nil {
+				return nil, err
+			}
+			return BootTimeRegressionValidator(opts...), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:         "clock_reset",
+			Description:  "validates that no boot-time is a default reported by a device whose clock was reset",
+			Tags:         []validation.Tag{validation.ClockResetBootTime},
+			Config:       bootTimeHistoryConfig,
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			opts, err := decodeBootTimeHistoryConfig(decode)
+			if err != nil {
+				return nil, err
+			}
+			return ClockResetValidator(opts...), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:         "boot_time_change",
+			Description:  "validates that the device went offline or came online whenever the boot-time changed",
+			Tags:         []validation.Tag{validation.SilentBootTimeChange},
+			Config:       bootTimeHistoryConfig,
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			opts, err := decodeBootTimeHistoryConfig(decode)
+			if err != nil {
+				return nil, err
+			}
+			return BootTimeChangeValidator(opts...), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "reboot_frequency",
+			Description: "validates that the device does not reboot or reconnect too often across the history",
+			Tags:        []validation.Tag{validation.RebootStorm, validation.ConnectionFlapping},
+			Config: []validation.ConfigField{
+				{Name: "window", Type: "duration", Description: "length of time that reboots and reconnects are counted over; required when either is limited"},
+				{Name: "maxReboots", Type: "int", Description: "most reboots allowed within the window; 0 for no limit"},
+				{Name: "maxReconnects", Type: "int", Description: "most reconnects without a reboot allowed within the window; 0 for no limit"},
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				RebootFrequency   `mapstructure:",squash"`
+				BootTimeTolerance time.Duration
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+
+			if err := config.RebootFrequency.Validate(); err != nil {
+				return nil, err
+			}
+			return RebootFrequencyValidator(config.RebootFrequency, WithBootTimeTolerance(config.BootTimeTolerance)), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "reboot_reason",
+			Description: "validates that the reason reported for each reboot agrees with whether a reboot-pending event announced it",
+			Tags:        []validation.Tag{validation.RebootReasonMismatch},
+			Config: []validation.ConfigField{
+				{Name: "taxonomy", Type: "map[string]RebootReason", Description: "reboot reasons mapped to their category and whether they are planned; defaults to DefaultRebootTaxonomy"},
+				{Name: "maxPendingDelay", Type: "duration", Description: "longest a reboot can be scheduled for after its reboot-pending event; 0 for no limit"},
+				{Name: "reasonKey", Type: "string", Description: "metadata key holding the reboot reason; defaults to hw-last-reboot-reason"},
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				RebootPolicy      `mapstructure:",squash"`
+				ReasonKey         string
+				BootTimeTolerance time.Duration
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return RebootReasonValidator(config.RebootPolicy, WithRebootReasonKey(config.ReasonKey), WithBootTimeTolerance(config.BootTimeTolerance)), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "reboot_schedule",
+			Description: "validates that reboots happen at the time scheduled by their reboot-pending events",
+			Tags:        []validation.Tag{validation.RebootScheduleDeviation},
+			Config: []validation.ConfigField{
+				{Name: "tolerance", Type: "duration", Description: "how far the offline event and the next boot-time can be from the scheduled time"},
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				Tolerance         time.Duration
+				BootTimeTolerance time.Duration
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return RebootScheduleValidator(config.Tolerance, WithBootTimeTolerance(config.BootTimeTolerance)), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "metadata_policy",
+			Description: "validates that metadata values only change within the scope and through the transitions allowed for their keys",
+			Tags:        []validation.Tag{validation.IllegalMetadataChange},
+			Config: []validation.ConfigField{
+				{Name: "policies", Type: "[]MetadataPolicy", Description: "metadata keys with their change scope (never, per-boot, per-session or any) and allowed transitions"},
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				Policies          []MetadataPolicy
+				BootTimeTolerance time.Duration
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+
+			for i, policy := range config.Policies {
+				scope, err := ParseChangeScope(string(policy.Scope))
+				if err != nil {
+					return nil, err
+				}
+				config.Policies[i].Scope = scope
+			}
+			return MetadataPolicyValidator(config.Policies, WithBootTimeTolerance(config.BootTimeTolerance)), nil
+		},
+	},
+	{
+		descriptor: validation.Descriptor{
+			Name:        "firmware",
+			Description: "validates that firmware versions are not downgraded, do not skip mandatory versions and stay within the range allowed for each model",
+			Tags:        []validation.Tag{validation.FirmwareDowngrade, validation.SkippedFirmwareVersion, validation.FirmwareOutOfRange},
+			Config: []validation.ConfigField{
+				{Name: "patterns", Type: "[]FirmwarePattern", Description: "per-model regexes that extract the major, minor and patch versions from fw-name"},
+				{Name: "rules", Type: "[]FirmwareRule", Description: "per-model minimum, maximum and mandatory firmware versions"},
+				{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+			},
+			WholeHistory: true,
+		},
+		factory: func(decode validation.ConfigDecoder) (CycleValidator, error) {
+			var config struct {
+				Patterns          []FirmwarePattern
+				Rules             []FirmwareRule
+				BootTimeTolerance time.Duration
+			}
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+
+			parser, err := NewFirmwareParser(config.Patterns)
+			if err != nil {
+				return nil, err
+			}
+			return FirmwareValidators(parser, config.Rules, WithBootTimeTolerance(config.BootTimeTolerance))
+		},
+	},
+}
+
+// duplicateKey returns the DuplicateKey configured by name.
+func duplicateKey(by string, ignoredFields []string) (DuplicateKey, error) {
+	switch strings.ToLower(by) {
+	case "", "content":
+		return FingerprintKey(ignoredFields...), nil
+	case "type":
+		return EventTypeKey(), nil
+	default:
+		return nil, fmt.Errorf("unknown duplicate key %s", by)
+	}
+}
+
+// bootTimeHistoryConfig is the configuration shared by the validators that check boot-times across a history.
+var bootTimeHistoryConfig = []validation.ConfigField{
+	{Name: "bootTimeTolerance", Type: "duration", Description: "how far apart boot-times can be while belonging to the same boot cycle"},
+	{Name: "clockResetBefore", Type: "string", Description: "RFC3339 time before which boot-times are clock-reset defaults; defaults to 2001-01-01T00:00:00Z"},
+}
+
+func decodeBootTimeHistoryConfig(decode validation.ConfigDecoder) ([]Option, error) {
+	var config struct {
+		BootTimeTolerance time.Duration
+		ClockResetBefore  string
+	}
+	if err := decode.Decode(&config); err != nil {
+		return nil, err
+	}
+
+	opts := []Option{WithBootTimeTolerance(config.BootTimeTolerance)}
+	if len(config.ClockResetBefore) > 0 {
+		before, err := time.Parse(time.RFC3339, config.ClockResetBefore)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithClockResetBefore(before))
+	}
+
+	return opts, nil
+}
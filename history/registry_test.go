@@ -0,0 +1,132 @@
+package history
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	factory := func(_ validation.ConfigDecoder) (CycleValidator, error) {
+		return DefaultCycleValidator(), nil
+	}
+
+	tests := []struct {
+		description string
+		descriptor  validation.Descriptor
+		factory     CycleValidatorFactory
+		expectedErr error
+	}{
+		{
+			description: "success",
+			descriptor:  validation.Descriptor{Name: "test"},
+			factory:     factory,
+		},
+		{
+			description: "empty name",
+			factory:     factory,
+			expectedErr: validation.ErrEmptyValidatorName,
+		},
+		{
+			description: "nil factory",
+			descriptor:  validation.Descriptor{Name: "test"},
+			expectedErr: validation.ErrNilFactory,
+		},
+		{
+			description: "duplicate name",
+			descriptor:  validation.Descriptor{Name: "existing"},
+			factory:     factory,
+			expectedErr: validation.ErrDuplicateValidatorName,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			registry := NewRegistry()
+			assert.Nil(registry.Register(validation.Descriptor{Name: "existing"}, factory))
+			err := registry.Register(tc.descriptor, tc.factory)
+			assert.True(errors.Is(err, tc.expectedErr))
+			if tc.expectedErr == nil {
+				descriptor, found := registry.Descriptor(tc.descriptor.Name)
+				assert.True(found)
+				assert.Equal(tc.descriptor, descriptor)
+			}
+		})
+	}
+}
+
+func TestRegistryNew(t *testing.T) {
+	assert := assert.New(t)
+	registry := DefaultRegistry()
+	events := []interpreter.Event{
+		interpreter.Event{Destination: "event:device-status/mac:112233445566/online"},
+		interpreter.Event{Destination: "event:device-status/mac:112233445566/operational"},
+	}
+
+	validator, err := registry.New("event_order", func(v interface{}) error {
+		v.(*struct{ Order []string }).Order = []string{"operational", "online"}
+		return nil
+	})
+	assert.Nil(err)
+	valid, _ := validator.Valid(events)
+	assert.False(valid)
+
+	validator, err = registry.New("event_order", nil)
+	assert.Nil(err)
+	valid, _ = validator.Valid(events)
+	assert.True(valid)
+
+	_, err = registry.New("random", nil)
+	assert.True(errors.Is(err, validation.ErrValidatorNotFound))
+
+	// a reboot limit without a window is rejected
+	_, err = registry.New("reboot_frequency", func(v interface{}) error {
+		reflect.ValueOf(v).Elem().FieldByName("MaxReboots").SetInt(3)
+		return nil
+	})
+	assert.True(errors.Is(err, ErrInvalidRebootWindow))
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	assert := assert.New(t)
+	registry := DefaultRegistry()
+	descriptors := registry.Descriptors()
+	assert.Equal(len(builtInCycleValidators), len(descriptors))
+	for i := 1; i < len(descriptors); i++ {
+		assert.Less(descriptors[i-1].Name, descriptors[i].Name)
+	}
+
+	for _, descriptor := range descriptors {
+		validator, err := registry.New(descriptor.Name, nil)
+		assert.Nil(err)
+		assert.NotNil(validator)
+		assert.NotEmpty(descriptor.Tags)
+	}
+
+	assert.Equal(validation.ErrEmptyValidatorName, Register(validation.Descriptor{}, nil))
+
+	// validators that compare boot cycles with each other or follow sessions across them must be run on the whole history
+	wholeHistory := map[string]bool{
+		"session_boot_time":    true,
+		"session_overlap":      true,
+		"session_event_order":  true,
+		"session_start":        true,
+		"true_reboot":          true,
+		"boot_time_regression": true,
+		"clock_reset":          true,
+		"boot_time_change":     true,
+		"reboot_frequency":     true,
+		"reboot_reason":        true,
+		"reboot_schedule":      true,
+		"metadata_policy":      true,
+		"firmware":             true,
+	}
+	for _, descriptor := range descriptors {
+		assert.Equal(wholeHistory[descriptor.Name], descriptor.WholeHistory, descriptor.Name)
+	}
+}
@@ -0,0 +1,433 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+const (
+	// AnyEventType is the event type in a sequence pattern that matches any event.
+	AnyEventType = "."
+
+	// Unbounded is the SequenceStep Max that allows a step to repeat any number of times.
+	Unbounded = -1
+)
+
+var (
+	ErrInvalidSequencePattern = errors.New("invalid sequence pattern")
+	ErrSequenceMismatch       = errors.New("events do not match sequence")
+)
+
+// SequenceStep is a single step of a SequencePattern.
+type SequenceStep struct {
+	// EventTypes are the alternative event types that the step matches, compared case-insensitively.
+	// AnyEventType matches any event, as does an empty list.
+	EventTypes []string
+
+	// Min and Max are the number of consecutive events that the step must match.
+	// A Max of Unbounded allows the step to match any number of events.
+	Min int
+	Max int
+
+	// Within is the longest time allowed between each event matched by the step and the event before it,
+	// based on the events' birthdates. A Within of 0 does not limit the time between events.
+	Within time.Duration
+}
+
+// String returns the step in the syntax used by ParseSequencePattern.
+func (s SequenceStep) String() string {
+	var output strings.Builder
+	if len(s.EventTypes) == 0 {
+		output.WriteString(AnyEventType)
+	} else {
+		output.WriteString(strings.Join(s.EventTypes, "|"))
+	}
+
+	switch {
+	case s.Min == 1 && s.Max == 1:
+	case s.Min == 0 && s.Max == 1:
+		output.WriteRune('?')
+	case s.Min == 0 && s.Max == Unbounded:
+		output.WriteRune('*')
+	case s.Min == 1 && s.Max == Unbounded:
+		output.WriteRune('+')
+	case s.Max == Unbounded:
+		output.WriteString(fmt.Sprintf("{%d,}", s.Min))
+	case s.Min == s.Max:
+		output.WriteString(fmt.Sprintf("{%d}", s.Min))
+	default:
+		output.WriteString(fmt.Sprintf("{%d,%d}", s.Min, s.Max))
+	}
+
+	if s.Within > 0 {
+		output.WriteString(fmt.Sprintf("<%s", s.Within))
+	}
+
+	return output.String()
+}
+
+// matches returns whether the event type is one of the step's event types.
+func (s SequenceStep) matches(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range s.EventTypes {
+		if t == AnyEventType || strings.EqualFold(t, eventType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SequencePattern is a list of steps that consecutive events must match in order.
+type SequencePattern []SequenceStep
+
+// String returns the pattern in the syntax used by ParseSequencePattern.
+func (p SequencePattern) String() string {
+	steps := make([]string, 0, len(p))
+	for _, step := range p {
+		steps = append(steps, step.String())
+	}
+
+	return strings.Join(steps, " ")
+}
+
+// ParseSequencePattern parses a pattern made of steps separated by whitespace. Each step is a list of
+// alternative event types separated by "|", where "." matches any event, optionally followed by a
+// repetition ("?", "*", "+", "{n}", "{n,}" or "{n,m}") and a time bound ("<" and a duration), such as:
+//
+//	online operational? fully-manageable|reboot-pending .* offline<5m
+func ParseSequencePattern(pattern string) (SequencePattern, error) {
+	fields := strings.Fields(pattern)
+	steps := make(SequencePattern, 0, len(fields))
+	for _, field := range fields {
+		step, err := parseSequenceStep(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSequencePattern, field, err)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+func parseSequenceStep(field string) (SequenceStep, error) {
+	step := SequenceStep{Min: 1, Max: 1}
+	if i := strings.IndexRune(field, '<'); i >= 0 {
+		within, err := time.ParseDuration(field[i+1:])
+		if err != nil {
+			return step, err
+		}
+
+		if within <= 0 {
+			return step, errors.New("time bound must be positive")
+		}
+
+		step.Within = within
+		field = field[:i]
+	}
+
+	switch {
+	case strings.HasSuffix(field, "?"):
+		step.Min, step.Max = 0, 1
+		field = strings.TrimSuffix(field, "?")
+	case strings.HasSuffix(field, "*"):
+		step.Min, step.Max = 0, Unbounded
+		field = strings.TrimSuffix(field, "*")
+	case strings.HasSuffix(field, "+"):
+		step.Min, step.Max = 1, Unbounded
+		field = strings.TrimSuffix(field, "+")
+	case strings.HasSuffix(field, "}"):
+		i := strings.LastIndex(field, "{")
+		if i < 0 {
+			return step, errors.New("missing {")
+		}
+
+		min, max, err := parseRepetition(field[i+1 : len(field)-1])
+		if err != nil {
+			return step, err
+		}
+
+		step.Min, step.Max = min, max
+		field = field[:i]
+	}
+
+	if len(field) == 0 {
+		return step, errors.New("missing event type")
+	}
+
+	for _, eventType := range strings.Split(field, "|") {
+		if len(eventType) == 0 {
+			return step, errors.New("empty event type")
+		}
+
+		if eventType == AnyEventType {
+			step.EventTypes = nil
+			break
+		}
+
+		step.EventTypes = append(step.EventTypes, eventType)
+	}
+
+	return step, nil
+}
+
+// parse the inside of a {n}, {n,} or {n,m} repetition.
+func parseRepetition(repetition string) (int, int, error) {
+	parts := strings.SplitN(repetition, ",", 2)
+	min, err := strconv.Atoi(parts[0])
+	if err != nil || min < 0 {
+		return 0, 0, fmt.Errorf("invalid repetition {%s}", repetition)
+	}
+
+	if len(parts) == 1 {
+		if min == 0 {
+			return 0, 0, fmt.Errorf("invalid repetition {%s}", repetition)
+		}
+
+		return min, min, nil
+	}
+
+	if len(parts[1]) == 0 {
+		return min, Unbounded, nil
+	}
+
+	max, err := strconv.Atoi(parts[1])
+	if err != nil || max < min || max == 0 {
+		return 0, 0, fmt.Errorf("invalid repetition {%s}", repetition)
+	}
+
+	return min, max, nil
+}
+
+// SequenceErr is returned by SequenceValidator and describes the closest the events came to matching the pattern.
+type SequenceErr struct {
+	// Matched are the events matched by the best partial match, from oldest to newest.
+	Matched []interpreter.Event
+
+	// Step is the index of the step in the pattern where the events deviated.
+	Step int
+
+	// Expected is the step in the pattern where the events deviated.
+	Expected SequenceStep
+
+	// Position is the index of the event where the events deviated in the list of events sorted
+	// from oldest to newest by birthdate. It is equal to the number of events if the events ended early.
+	Position int
+
+	// Found is the event where the events deviated, if the events did not end early.
+	Found *interpreter.Event
+
+	// Elapsed is the time between the Found event and the event before it, if the step's time bound was exceeded.
+	Elapsed time.Duration
+}
+
+func (e SequenceErr) Error() string {
+	return fmt.Sprintf("%v: %s", ErrSequenceMismatch, e.Deviation())
+}
+
+func (e SequenceErr) Unwrap() error {
+	return ErrSequenceMismatch
+}
+
+// Tag implements the TaggedError interface.
+func (e SequenceErr) Tag() validation.Tag {
+	return validation.InvalidEventOrder
+}
+
+// Deviation describes where and how the events deviated from the pattern.
+func (e SequenceErr) Deviation() string {
+	if e.Found == nil {
+		return fmt.Sprintf("expected %s at position %d, found end of events", e.Expected, e.Position)
+	}
+
+	eventType, _ := e.Found.EventType()
+	if e.Elapsed > 0 {
+		return fmt.Sprintf("expected %s at position %d, found %s (%s) after %s", e.Expected, e.Position, eventType, e.Found.TransactionUUID, e.Elapsed)
+	}
+
+	return fmt.Sprintf("expected %s at position %d, found %s (%s)", e.Expected, e.Position, eventType, e.Found.TransactionUUID)
+}
+
+// SequenceValidator returns a CycleValidatorFunc that validates that, within the events sorted from oldest
+// to newest by birthdate, there is a run of consecutive events that matches the pattern. If no run matches,
+// the error details contain the events of the best partial match and the point where the events deviated
+// from the pattern. Use AnyEventType steps to allow other events in between the steps of the pattern.
+func SequenceValidator(pattern SequencePattern) CycleValidatorFunc {
+	return func(events []interpreter.Event) (bool, error) {
+		if len(pattern) == 0 {
+			return true, nil
+		}
+
+		eventsCopy := make([]interpreter.Event, len(events))
+		copy(eventsCopy, events)
+		sort.SliceStable(eventsCopy, func(a, b int) bool {
+			return eventsCopy[a].Birthdate < eventsCopy[b].Birthdate
+		})
+
+		m := sequenceMatcher{pattern: pattern, events: eventsCopy, best: sequenceProgress{step: -1}}
+		for start := 0; start < len(eventsCopy) || start == 0; start++ {
+			m.start = start
+			m.failed = make(map[[3]int]bool)
+			if m.match(0, 0, start) {
+				return true, nil
+			}
+		}
+
+		seqErr := m.err()
+		details := make([]string, 0, len(seqErr.Matched)+1)
+		for _, event := range seqErr.Matched {
+			eventType, _ := event.EventType()
+			details = append(details, fmt.Sprintf("matched %s (%s)", eventType, event.TransactionUUID))
+		}
+		details = append(details, seqErr.Deviation())
+
+		// the event where the events deviated from the pattern, or the events matched so far if they ran out
+		offending := seqErr.Matched
+		if seqErr.Found != nil {
+			offending = []interpreter.Event{*seqErr.Found}
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       seqErr,
+			ErrorDetailKey:    "sequence",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.InvalidEventOrder,
+			OffendingEvents:   offending,
+		}
+	}
+}
+
+// sequenceProgress is how far a match got: the steps before step were matched, along with
+// count events of step, by the events from start up to pos.
+type sequenceProgress struct {
+	start int
+	step  int
+	count int
+	pos   int
+}
+
+type sequenceMatcher struct {
+	pattern SequencePattern
+	events  []interpreter.Event
+	start   int
+	failed  map[[3]int]bool
+	best    sequenceProgress
+}
+
+// match reports whether the steps from step onwards match the events from pos onwards,
+// with count events already matched by step.
+func (m *sequenceMatcher) match(step int, count int, pos int) bool {
+	if step == len(m.pattern) {
+		return true
+	}
+
+	m.record(step, count, pos)
+	s := m.pattern[step]
+
+	// only the number of repetitions up to the step's minimum changes what can match next
+	// for unbounded steps, so cap it to keep the number of states small.
+	key := [3]int{step, count, pos}
+	if s.Max == Unbounded && count > s.Min {
+		key[1] = s.Min
+	}
+
+	if m.failed[key] {
+		return false
+	}
+
+	if (s.Max == Unbounded || count < s.Max) && m.accepts(s, pos) && m.match(step, count+1, pos+1) {
+		return true
+	}
+
+	if count >= s.Min && m.match(step+1, 0, pos) {
+		return true
+	}
+
+	m.failed[key] = true
+	return false
+}
+
+// accepts returns whether the event at pos can be matched by the step.
+func (m *sequenceMatcher) accepts(s SequenceStep, pos int) bool {
+	if pos >= len(m.events) {
+		return false
+	}
+
+	eventType, err := m.events[pos].EventType()
+	if err != nil || !s.matches(eventType) {
+		return false
+	}
+
+	return m.elapsed(s, m.start, pos) == 0
+}
+
+// elapsed returns the time between the event at pos and the event before it if it exceeds
+// the step's time bound, or 0 otherwise. The first event of a match, at start, is never bound.
+func (m *sequenceMatcher) elapsed(s SequenceStep, start int, pos int) time.Duration {
+	if s.Within <= 0 || pos <= start || pos >= len(m.events) {
+		return 0
+	}
+
+	elapsed := time.Duration(m.events[pos].Birthdate - m.events[pos-1].Birthdate)
+	if elapsed > s.Within {
+		return elapsed
+	}
+
+	return 0
+}
+
+// record saves the progress if it is further along the pattern than the best progress so far.
+func (m *sequenceMatcher) record(step int, count int, pos int) {
+	if step > m.best.step || (step == m.best.step && pos-m.start > m.best.pos-m.best.start) {
+		m.best = sequenceProgress{start: m.start, step: step, count: count, pos: pos}
+	}
+}
+
+// err builds the SequenceErr from the best progress.
+func (m *sequenceMatcher) err() SequenceErr {
+	best := m.best
+	step := m.pattern[best.step]
+	seqErr := SequenceErr{
+		Matched:  m.events[best.start:best.pos],
+		Step:     best.step,
+		Expected: step,
+		Position: best.pos,
+	}
+
+	if best.pos < len(m.events) {
+		seqErr.Found = &m.events[best.pos]
+		if eventType, err := seqErr.Found.EventType(); err == nil && step.matches(eventType) {
+			seqErr.Elapsed = m.elapsed(step, best.start, best.pos)
+		}
+	}
+
+	return seqErr
+}
@@ -0,0 +1,243 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestParseSequencePattern(t *testing.T) {
+	tests := []struct {
+		description string
+		pattern     string
+		expected    SequencePattern
+		expectedErr error
+	}{
+		{
+			description: "single steps",
+			pattern:     "online offline",
+			expected: SequencePattern{
+				{EventTypes: []string{"online"}, Min: 1, Max: 1},
+				{EventTypes: []string{"offline"}, Min: 1, Max: 1},
+			},
+		},
+		{
+			description: "repetition",
+			pattern:     "a? b* c+ d{2} e{2,} f{1,3}",
+			expected: SequencePattern{
+				{EventTypes: []string{"a"}, Min: 0, Max: 1},
+				{EventTypes: []string{"b"}, Min: 0, Max: Unbounded},
+				{EventTypes: []string{"c"}, Min: 1, Max: Unbounded},
+				{EventTypes: []string{"d"}, Min: 2, Max: 2},
+				{EventTypes: []string{"e"}, Min: 2, Max: Unbounded},
+				{EventTypes: []string{"f"}, Min: 1, Max: 3},
+			},
+		},
+		{
+			description: "alternatives, wildcard and time bound",
+			pattern:     "operational|fully-manageable  .*  offline?<5m",
+			expected: SequencePattern{
+				{EventTypes: []string{"operational", "fully-manageable"}, Min: 1, Max: 1},
+				{Min: 0, Max: Unbounded},
+				{EventTypes: []string{"offline"}, Min: 0, Max: 1, Within: 5 * time.Minute},
+			},
+		},
+		{
+			description: "empty",
+			pattern:     "",
+			expected:    SequencePattern{},
+		},
+		{
+			description: "invalid duration",
+			pattern:     "online offline<soon",
+			expectedErr: ErrInvalidSequencePattern,
+		},
+		{
+			description: "negative duration",
+			pattern:     "offline<-5m",
+			expectedErr: ErrInvalidSequencePattern,
+		},
+		{
+			description: "invalid repetition",
+			pattern:     "online{3,1}",
+			expectedErr: ErrInvalidSequencePattern,
+		},
+		{
+			description: "zero repetition",
+			pattern:     "online{0}",
+			expectedErr: ErrInvalidSequencePattern,
+		},
+		{
+			description: "missing event type",
+			pattern:     "online *",
+			expectedErr: ErrInvalidSequencePattern,
+		},
+		{
+			description: "empty alternative",
+			pattern:     "online||offline",
+			expectedErr: ErrInvalidSequencePattern,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			pattern, err := ParseSequencePattern(tc.pattern)
+			if tc.expectedErr != nil {
+				assert.True(errors.Is(err, tc.expectedErr))
+				return
+			}
+
+			assert.Nil(err)
+			assert.Equal(tc.expected, pattern)
+		})
+	}
+}
+
+func TestSequencePatternString(t *testing.T) {
+	pattern := "online operational? .* fully-manageable|reboot-pending+ a{2} b{2,} c{1,3} offline<5m0s"
+	parsed, err := ParseSequencePattern(pattern)
+	assert.Nil(t, err)
+	assert.Equal(t, pattern, parsed.String())
+}
+
+func TestSequenceValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("5", "offline", bootTime, now.Add(-60*time.Minute), "a"),
+		testCycleEvent("4", "reboot-pending", bootTime, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("3", "fully-manageable", bootTime, now.Add(-80*time.Minute), "a"),
+		testCycleEvent("2", "operational", bootTime, now.Add(-90*time.Minute), "a"),
+		testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+	}
+
+	tests := []struct {
+		description       string
+		pattern           string
+		events            []interpreter.Event
+		expectedMatched   []string
+		expectedStep      int
+		expectedPosition  int
+		expectedFound     string
+		expectedElapsed   time.Duration
+		expectedDeviation string
+	}{
+		{
+			description: "exact",
+			pattern:     "online operational fully-manageable reboot-pending offline",
+			events:      events,
+		},
+		{
+			description: "empty pattern",
+			pattern:     "",
+			events:      events,
+		},
+		{
+			description: "match in the middle",
+			pattern:     "operational fully-manageable",
+			events:      events,
+		},
+		{
+			description: "optional and alternatives",
+			pattern:     "online OPERATIONAL? reboot-pending|fully-manageable+ offline",
+			events:      events,
+		},
+		{
+			description: "wildcard",
+			pattern:     "online .* offline",
+			events:      events,
+		},
+		{
+			description: "within time bound",
+			pattern:     "online .{3}<20m offline<10m",
+			events:      events,
+		},
+		{
+			description:       "repetition too short",
+			pattern:           "online .{5}",
+			events:            events,
+			expectedMatched:   []string{"1", "2", "3", "4", "5"},
+			expectedStep:      1,
+			expectedPosition:  5,
+			expectedDeviation: "expected .{5} at position 5, found end of events",
+		},
+		{
+			description:       "deviation",
+			pattern:           "online operational reboot-pending offline",
+			events:            events,
+			expectedMatched:   []string{"1", "2"},
+			expectedStep:      2,
+			expectedPosition:  2,
+			expectedFound:     "3",
+			expectedDeviation: "expected reboot-pending at position 2, found fully-manageable (3)",
+		},
+		{
+			description:       "time bound exceeded",
+			pattern:           "online operational<10m",
+			events:            events,
+			expectedMatched:   []string{"1"},
+			expectedStep:      1,
+			expectedPosition:  1,
+			expectedFound:     "2",
+			expectedElapsed:   20 * time.Minute,
+			expectedDeviation: "expected operational<10m0s at position 1, found operational (2) after 20m0s",
+		},
+		{
+			description:       "best partial match",
+			pattern:           "fully-manageable|operational offline",
+			events:            events,
+			expectedMatched:   []string{"2"},
+			expectedStep:      1,
+			expectedPosition:  2,
+			expectedFound:     "3",
+			expectedDeviation: "expected offline at position 2, found fully-manageable (3)",
+		},
+		{
+			description:       "no events",
+			pattern:           "online",
+			expectedMatched:   []string{},
+			expectedStep:      0,
+			expectedPosition:  0,
+			expectedDeviation: "expected online at position 0, found end of events",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			pattern, err := ParseSequencePattern(tc.pattern)
+			assert.Nil(err)
+			valid, err := SequenceValidator(pattern).Valid(tc.events)
+			if len(tc.expectedDeviation) == 0 {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			var cvErr CycleValidationErr
+			assert.True(errors.As(err, &cvErr))
+			assert.Equal(validation.InvalidEventOrder, cvErr.Tag())
+			assert.Equal(tc.expectedDeviation, cvErr.Fields()[len(cvErr.Fields())-1])
+
+			var seqErr SequenceErr
+			assert.True(errors.As(err, &seqErr))
+			assert.True(errors.Is(err, ErrSequenceMismatch))
+			assert.Equal(tc.expectedMatched, testEventIDs(seqErr.Matched))
+			assert.Equal(tc.expectedStep, seqErr.Step)
+			assert.Equal(tc.expectedPosition, seqErr.Position)
+			assert.Equal(tc.expectedElapsed, seqErr.Elapsed)
+			if len(tc.expectedFound) > 0 {
+				assert.Equal(tc.expectedFound, seqErr.Found.TransactionUUID)
+			} else {
+				assert.Nil(seqErr.Found)
+			}
+		})
+	}
+}
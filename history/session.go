@@ -0,0 +1,186 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// SessionState describes how much of a session can be seen in a history.
+type SessionState int
+
+const (
+	// SessionTruncated is a session whose online event is not in the history, most likely
+	// because the session started before the history begins.
+	SessionTruncated SessionState = iota
+
+	// SessionOpen is a session with an online event but no offline event.
+	SessionOpen
+
+	// SessionClosed is a session with both an online and an offline event.
+	SessionClosed
+)
+
+func (s SessionState) String() string {
+	switch s {
+	case SessionOpen:
+		return "open"
+	case SessionClosed:
+		return "closed"
+	default:
+		return "truncated"
+	}
+}
+
+// Session is a device's connection, made up of all of the events that share a session id.
+type Session struct {
+	ID       string
+	BootTime int64
+
+	// Events are sorted from oldest to newest by birthdate.
+	Events []interpreter.Event
+
+	online     interpreter.Event
+	hasOnline  bool
+	offline    interpreter.Event
+	hasOffline bool
+}
+
+// Sessions groups a list of events by session id. Events without a session id or a
+// parsable event type are not included in any session. The returned sessions are sorted
+// from oldest to newest by the birthdate of their first event. A session's online and offline
+// events are the event types with the session start and session end roles in the event type registry.
+func Sessions(events []interpreter.Event, opts ...Option) []Session {
+	o := newOptions(opts)
+	sessionsMap := make(map[string][]interpreter.Event)
+	for _, event := range events {
+		if _, err := event.EventType(); err != nil || len(event.SessionID) == 0 {
+			continue
+		}
+
+		sessionsMap[event.SessionID] = append(sessionsMap[event.SessionID], event)
+	}
+
+	sessions := make([]Session, 0, len(sessionsMap))
+	for id, sessionEvents := range sessionsMap {
+		sessions = append(sessions, newSession(id, sessionEvents, o))
+	}
+
+	sort.Slice(sessions, func(a, b int) bool {
+		return sessions[a].Events[0].Birthdate < sessions[b].Events[0].Birthdate
+	})
+
+	return sessions
+}
+
+// FindSession returns the session in the list of events that contains the event passed in.
+// False is returned if the event does not have a session id.
+func FindSession(events []interpreter.Event, event interpreter.Event, opts ...Option) (Session, bool) {
+	if len(event.SessionID) == 0 {
+		return Session{}, false
+	}
+
+	var sessionEvents []interpreter.Event
+	found := false
+	for _, e := range events {
+		if e.SessionID != event.SessionID {
+			continue
+		}
+
+		if sameEvent(e, event) {
+			found = true
+		} else if _, err := e.EventType(); err != nil {
+			continue
+		}
+
+		sessionEvents = append(sessionEvents, e)
+	}
+
+	if !found {
+		sessionEvents = append(sessionEvents, event)
+	}
+
+	return newSession(event.SessionID, sessionEvents, newOptions(opts)), true
+}
+
+func newSession(id string, events []interpreter.Event, o options) Session {
+	sort.SliceStable(events, func(a, b int) bool {
+		return events[a].Birthdate < events[b].Birthdate
+	})
+
+	session := Session{ID: id, Events: events}
+	for _, event := range events {
+		if o.hasRole(event, interpreter.SessionStartRole) && !session.hasOnline {
+			session.online = event
+			session.hasOnline = true
+		} else if o.hasRole(event, interpreter.SessionEndRole) {
+			session.offline = event
+			session.hasOffline = true
+		}
+	}
+
+	if session.hasOnline {
+		session.BootTime, _ = session.online.BootTime()
+	}
+
+	for i := 0; i < len(events) && session.BootTime <= 0; i++ {
+		session.BootTime, _ = events[i].BootTime()
+	}
+
+	if session.BootTime < 0 {
+		session.BootTime = 0
+	}
+
+	return session
+}
+
+// Online returns the session's first online event, if it exists.
+func (s Session) Online() (interpreter.Event, bool) {
+	return s.online, s.hasOnline
+}
+
+// Offline returns the session's last offline event, if it exists.
+func (s Session) Offline() (interpreter.Event, bool) {
+	return s.offline, s.hasOffline
+}
+
+// State returns whether the session is open, closed or truncated by the history.
+func (s Session) State() SessionState {
+	if !s.hasOnline {
+		return SessionTruncated
+	}
+
+	if !s.hasOffline {
+		return SessionOpen
+	}
+
+	return SessionClosed
+}
+
+// Duration returns how long the device was connected during the session, from the online event
+// to the offline event. False is returned if the session is not closed.
+func (s Session) Duration() (time.Duration, bool) {
+	if s.State() != SessionClosed {
+		return 0, false
+	}
+
+	return time.Duration(s.offline.Birthdate - s.online.Birthdate), true
+}
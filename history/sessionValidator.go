@@ -0,0 +1,209 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrSessionBootTimeMismatch = errors.New("session spans multiple boot-times")
+	ErrOverlappingSessions     = errors.New("sessions overlap")
+	ErrOfflineBeforeOnline     = errors.New("session offline event before online event")
+	ErrSessionStartedWhileOpen = errors.New("session started while previous session was open")
+)
+
+// SessionValidators returns the validators that check the integrity of the sessions in a history: SessionOnlineValidator,
+// SessionOfflineValidator, SessionBootTimeValidator, SessionOverlapValidator, SessionEventOrderValidator and
+// SessionStartValidator. The excludeFunc is passed to each validator.
+func SessionValidators(excludeFunc func(events []interpreter.Event, id string) bool, opts ...Option) CycleValidators {
+	return CycleValidators{
+		SessionOnlineValidator(excludeFunc, opts...),
+		SessionOfflineValidator(excludeFunc, opts...),
+		SessionBootTimeValidator(excludeFunc, opts...),
+		SessionOverlapValidator(excludeFunc, opts...),
+		SessionEventOrderValidator(excludeFunc, opts...),
+		SessionStartValidator(excludeFunc, opts...),
+	}
+}
+
+// SessionBootTimeValidator returns a CycleValidatorFunc that validates that the events of each session share a
+// boot-time, since a device must start a new session after it reboots. Boot-times within the boot-time tolerance
+// of each other are considered the same, and events without a boot-time are skipped. It takes in excludeFunc,
+// which is a function that takes in a session ID and returns true if that session is still valid.
+func SessionBootTimeValidator(excludeFunc func(events []interpreter.Event, id string) bool, opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
+	return func(events []interpreter.Event) (bool, error) {
+		clusters := o.clusterBootTimes(events)
+		var invalidIds []string
+		for _, session := range Sessions(events, opts...) {
+			bootTimes := make(map[int64]bool)
+			for _, event := range session.Events {
+				if bootTime, _ := clusters.BootTime(event); bootTime > 0 {
+					bootTimes[bootTime] = true
+				}
+			}
+
+			if len(bootTimes) > 1 && !excluded(excludeFunc, events, session.ID) {
+				invalidIds = append(invalidIds, session.ID)
+			}
+		}
+
+		if len(invalidIds) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrSessionBootTimeMismatch,
+			ErrorDetailKey:    "session ids",
+			ErrorDetailValues: invalidIds,
+			ErrorTag:          validation.SessionBootTimeMismatch,
+			OffendingEvents:   sessionEvents(events, invalidIds),
+		}
+	}
+}
+
+// SessionOverlapValidator returns a CycleValidatorFunc that validates that sessions do not overlap in time,
+// meaning that no session has an event born between the first and last events of another session. Each pair
+// of overlapping sessions is reported, oldest session first. It takes in excludeFunc, which is a function that
+// takes in a session ID and returns true if that session is still valid.
+func SessionOverlapValidator(excludeFunc func(events []interpreter.Event, id string) bool, opts ...Option) CycleValidatorFunc {
+	return func(events []interpreter.Event) (bool, error) {
+		sessions := Sessions(events, opts...)
+		var overlaps []string
+		var overlapIds []string
+		for i, session := range sessions {
+			if excluded(excludeFunc, events, session.ID) {
+				continue
+			}
+
+			last := session.Events[len(session.Events)-1].Birthdate
+			// sessions are sorted by the birthdates of their first events, so only later sessions can start
+			// before this one ends
+			for _, other := range sessions[i+1:] {
+				if other.Events[0].Birthdate > last {
+					break
+				}
+
+				if !excluded(excludeFunc, events, other.ID) {
+					overlaps = append(overlaps, fmt.Sprintf("%s, %s", session.ID, other.ID))
+					overlapIds = append(overlapIds, session.ID, other.ID)
+				}
+			}
+		}
+
+		if len(overlaps) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrOverlappingSessions,
+			ErrorDetailKey:    "overlapping session ids",
+			ErrorDetailValues: overlaps,
+			ErrorTag:          validation.OverlappingSessions,
+			OffendingEvents:   sessionEvents(events, overlapIds),
+		}
+	}
+}
+
+// SessionEventOrderValidator returns a CycleValidatorFunc that validates that no session has an offline event
+// born before its online event, where online and offline events are the event types with the session start and
+// session end roles in the event type registry. It takes in excludeFunc, which is a function that takes in a
+// session ID and returns true if that session is still valid.
+func SessionEventOrderValidator(excludeFunc func(events []interpreter.Event, id string) bool, opts ...Option) CycleValidatorFunc {
+	o := newOptions(opts)
+	return func(events []interpreter.Event) (bool, error) {
+		var invalidIds []string
+		for _, session := range Sessions(events, opts...) {
+			online, found := session.Online()
+			if !found {
+				continue
+			}
+
+			for _, event := range session.Events {
+				if o.hasRole(event, interpreter.SessionEndRole) && event.Birthdate < online.Birthdate {
+					if !excluded(excludeFunc, events, session.ID) {
+						invalidIds = append(invalidIds, session.ID)
+					}
+					break
+				}
+			}
+		}
+
+		if len(invalidIds) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrOfflineBeforeOnline,
+			ErrorDetailKey:    "session ids",
+			ErrorDetailValues: invalidIds,
+			ErrorTag:          validation.OfflineBeforeOnline,
+			OffendingEvents:   sessionEvents(events, invalidIds),
+		}
+	}
+}
+
+// SessionStartValidator returns a CycleValidatorFunc that validates that a session only starts once the session before
+// it has ended, meaning that the previous session has an offline event born before the new session's online event.
+// Sessions without an online event are skipped. Each pair of sessions is reported, previous session first. It takes
+// in excludeFunc, which is a function that takes in a session ID and returns true if that session is still valid.
+func SessionStartValidator(excludeFunc func(events []interpreter.Event, id string) bool, opts ...Option) CycleValidatorFunc {
+	return func(events []interpreter.Event) (bool, error) {
+		var invalid []string
+		var invalidIds []string
+		var previous *Session
+		for _, session := range Sessions(events, opts...) {
+			session := session
+			online, found := session.Online()
+			if !found {
+				continue
+			}
+
+			if previous != nil && !excluded(excludeFunc, events, session.ID) {
+				if offline, found := previous.Offline(); !found || offline.Birthdate > online.Birthdate {
+					invalid = append(invalid, fmt.Sprintf("%s, %s", previous.ID, session.ID))
+					invalidIds = append(invalidIds, previous.ID, session.ID)
+				}
+			}
+
+			previous = &session
+		}
+
+		if len(invalid) == 0 {
+			return true, nil
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       ErrSessionStartedWhileOpen,
+			ErrorDetailKey:    "session ids",
+			ErrorDetailValues: invalid,
+			ErrorTag:          validation.SessionStartedWhileOpen,
+			OffendingEvents:   sessionEvents(events, invalidIds),
+		}
+	}
+}
+
+// excluded returns whether the session is excluded from validation by the exclude function.
+func excluded(exclude func(events []interpreter.Event, id string) bool, events []interpreter.Event, id string) bool {
+	return exclude != nil && exclude(events, id)
+}
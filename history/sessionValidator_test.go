@@ -0,0 +1,252 @@
+package history
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestSessionBootTimeValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	tests := []struct {
+		description string
+		events      []interpreter.Event
+		exclude     func([]interpreter.Event, string) bool
+		opts        []Option
+		expectedIds []string
+	}{
+		{
+			description: "valid",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "offline", bootTime1, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("3", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+				testCycleEvent("4", "operational", 0, now.Add(-40*time.Minute), "b"),
+			},
+		},
+		{
+			description: "multiple boot-times",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "operational", bootTime2, now.Add(-50*time.Minute), "a"),
+			},
+			expectedIds: []string{"a"},
+		},
+		{
+			description: "excluded",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "operational", bootTime2, now.Add(-50*time.Minute), "a"),
+			},
+			exclude: func(_ []interpreter.Event, id string) bool { return id == "a" },
+		},
+		{
+			description: "within tolerance",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "operational", bootTime1+1, now.Add(-50*time.Minute), "a"),
+			},
+			opts: []Option{WithBootTimeTolerance(time.Second)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			testSessionValidator(t, SessionBootTimeValidator(tc.exclude, tc.opts...), tc.events, validation.SessionBootTimeMismatch, ErrSessionBootTimeMismatch, tc.expectedIds)
+		})
+	}
+}
+
+func TestSessionOverlapValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	tests := []struct {
+		description string
+		events      []interpreter.Event
+		exclude     func([]interpreter.Event, string) bool
+		expectedIds []string
+	}{
+		{
+			description: "valid",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "offline", bootTime, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("3", "online", bootTime, now.Add(-50*time.Minute), "b"),
+			},
+		},
+		{
+			description: "empty",
+		},
+		{
+			description: "overlap",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-100*time.Minute), "b"),
+				testCycleEvent("3", "online", bootTime, now.Add(-90*time.Minute), "c"),
+				testCycleEvent("4", "offline", bootTime, now.Add(-80*time.Minute), "a"),
+				testCycleEvent("5", "offline", bootTime, now.Add(-85*time.Minute), "b"),
+			},
+			expectedIds: []string{"a, b", "a, c", "b, c"},
+		},
+		{
+			description: "excluded",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-100*time.Minute), "b"),
+				testCycleEvent("3", "offline", bootTime, now.Add(-80*time.Minute), "a"),
+			},
+			exclude: func(_ []interpreter.Event, id string) bool { return id == "b" },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			testSessionValidator(t, SessionOverlapValidator(tc.exclude), tc.events, validation.OverlappingSessions, ErrOverlappingSessions, tc.expectedIds)
+		})
+	}
+}
+
+func TestSessionEventOrderValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	tests := []struct {
+		description string
+		events      []interpreter.Event
+		exclude     func([]interpreter.Event, string) bool
+		expectedIds []string
+	}{
+		{
+			description: "valid",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "offline", bootTime, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("3", "offline", bootTime, now.Add(-60*time.Minute), "b"),
+			},
+		},
+		{
+			description: "offline before online",
+			events: []interpreter.Event{
+				testCycleEvent("1", "offline", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("3", "online", bootTime, now.Add(-60*time.Minute), "b"),
+				testCycleEvent("4", "offline", bootTime, now.Add(-65*time.Minute), "b"),
+			},
+			expectedIds: []string{"a", "b"},
+		},
+		{
+			description: "excluded",
+			events: []interpreter.Event{
+				testCycleEvent("1", "offline", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-70*time.Minute), "a"),
+			},
+			exclude: func(_ []interpreter.Event, id string) bool { return id == "a" },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			testSessionValidator(t, SessionEventOrderValidator(tc.exclude), tc.events, validation.OfflineBeforeOnline, ErrOfflineBeforeOnline, tc.expectedIds)
+		})
+	}
+}
+
+func TestSessionStartValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	tests := []struct {
+		description string
+		events      []interpreter.Event
+		exclude     func([]interpreter.Event, string) bool
+		expectedIds []string
+	}{
+		{
+			description: "valid",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "offline", bootTime, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("3", "online", bootTime, now.Add(-50*time.Minute), "b"),
+				testCycleEvent("4", "operational", bootTime, now.Add(-40*time.Minute), "c"),
+			},
+		},
+		{
+			description: "previous session open",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-70*time.Minute), "b"),
+				testCycleEvent("3", "offline", bootTime, now.Add(-60*time.Minute), "b"),
+				testCycleEvent("4", "online", bootTime, now.Add(-65*time.Minute), "c"),
+			},
+			expectedIds: []string{"a, b", "b, c"},
+		},
+		{
+			description: "excluded",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime, now.Add(-70*time.Minute), "b"),
+			},
+			exclude: func(_ []interpreter.Event, id string) bool { return id == "b" },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			testSessionValidator(t, SessionStartValidator(tc.exclude), tc.events, validation.SessionStartedWhileOpen, ErrSessionStartedWhileOpen, tc.expectedIds)
+		})
+	}
+}
+
+func TestSessionValidators(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-2 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime, now.Add(-110*time.Minute), "a"),
+		testCycleEvent("2", "offline", bootTime, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("3", "online", bootTime, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("4", "offline", bootTime, now.Add(-40*time.Minute), "b"),
+	}
+
+	valid, err := SessionValidators(nil).Valid(events)
+	assert.True(t, valid)
+	assert.Nil(t, err)
+
+	valid, err = SessionValidators(nil).Valid([]interpreter.Event{events[0], events[2], events[3]})
+	assert.False(t, valid)
+	var errs validation.Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, []validation.Tag{validation.MissingOfflineEvent, validation.SessionStartedWhileOpen}, errs.Tags())
+}
+
+func testSessionValidator(t *testing.T, validator CycleValidator, events []interpreter.Event, expectedTag validation.Tag, expectedErr error, expectedIds []string) {
+	assert := assert.New(t)
+	valid, err := validator.Valid(events)
+	if len(expectedIds) == 0 {
+		assert.True(valid)
+		assert.Nil(err)
+		return
+	}
+
+	assert.False(valid)
+	assert.True(errors.Is(err, expectedErr))
+	var cvErr CycleValidationErr
+	assert.True(errors.As(err, &cvErr))
+	assert.Equal(expectedTag, cvErr.Tag())
+	assert.ElementsMatch(expectedIds, cvErr.Fields())
+
+	ids := strings.Join(expectedIds, ", ")
+	assert.NotEmpty(cvErr.Events())
+	for _, event := range cvErr.Events() {
+		assert.Contains(ids, event.SessionID)
+	}
+}
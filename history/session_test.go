@@ -0,0 +1,126 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestSessions(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("4", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+		testCycleEvent("2", "offline", bootTime1, now.Add(-70*time.Minute), "a"),
+		testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+		testCycleEvent("3", "operational", bootTime1, now.Add(-100*time.Minute), "a"),
+		testCycleEvent("5", "operational", bootTime2, now.Add(-40*time.Minute), "b"),
+		testCycleEvent("6", "offline", bootTime2, now.Add(-30*time.Minute), "c"),
+		testCycleEvent("7", "operational", bootTime2, now.Add(-20*time.Minute), ""),
+		interpreter.Event{TransactionUUID: "8", Destination: "non-event", SessionID: "d"},
+	}
+
+	sessions := Sessions(events)
+	assert.Len(sessions, 3)
+
+	assert.Equal("a", sessions[0].ID)
+	assert.Equal(bootTime1, sessions[0].BootTime)
+	assert.Equal([]string{"1", "3", "2"}, testEventIDs(sessions[0].Events))
+	assert.Equal(SessionClosed, sessions[0].State())
+	online, found := sessions[0].Online()
+	assert.True(found)
+	assert.Equal("1", online.TransactionUUID)
+	offline, found := sessions[0].Offline()
+	assert.True(found)
+	assert.Equal("2", offline.TransactionUUID)
+	duration, closed := sessions[0].Duration()
+	assert.True(closed)
+	assert.Equal(40*time.Minute, duration)
+
+	assert.Equal("b", sessions[1].ID)
+	assert.Equal(bootTime2, sessions[1].BootTime)
+	assert.Equal(SessionOpen, sessions[1].State())
+	_, found = sessions[1].Offline()
+	assert.False(found)
+	_, closed = sessions[1].Duration()
+	assert.False(closed)
+
+	assert.Equal("c", sessions[2].ID)
+	assert.Equal(bootTime2, sessions[2].BootTime)
+	assert.Equal(SessionTruncated, sessions[2].State())
+	_, found = sessions[2].Online()
+	assert.False(found)
+
+	assert.Empty(Sessions(nil))
+}
+
+func TestFindSession(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime := now.Add(-1 * time.Hour).Unix()
+	events := []interpreter.Event{
+		testCycleEvent("1", "online", bootTime, now.Add(-50*time.Minute), "a"),
+		testCycleEvent("2", "offline", bootTime, now.Add(-40*time.Minute), "a"),
+		testCycleEvent("3", "online", bootTime, now.Add(-30*time.Minute), "b"),
+		interpreter.Event{TransactionUUID: "4", Destination: "non-event", SessionID: "b"},
+	}
+
+	tests := []struct {
+		description   string
+		event         interpreter.Event
+		expectedFound bool
+		expectedIDs   []string
+		expectedState SessionState
+	}{
+		{
+			description:   "event in history",
+			event:         events[1],
+			expectedFound: true,
+			expectedIDs:   []string{"1", "2"},
+			expectedState: SessionClosed,
+		},
+		{
+			description:   "event not in history",
+			event:         testCycleEvent("5", "offline", bootTime, now.Add(-20*time.Minute), "b"),
+			expectedFound: true,
+			expectedIDs:   []string{"3", "5"},
+			expectedState: SessionClosed,
+		},
+		{
+			description:   "new session",
+			event:         testCycleEvent("5", "online", bootTime, now, "c"),
+			expectedFound: true,
+			expectedIDs:   []string{"5"},
+			expectedState: SessionOpen,
+		},
+		{
+			description: "no session id",
+			event:       testCycleEvent("5", "online", bootTime, now, ""),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			session, found := FindSession(events, tc.event)
+			assert.Equal(tc.expectedFound, found)
+			if tc.expectedFound {
+				assert.Equal(tc.event.SessionID, session.ID)
+				assert.Equal(tc.expectedIDs, testEventIDs(session.Events))
+				assert.Equal(tc.expectedState, session.State())
+			}
+		})
+	}
+}
+
+func TestSessionStateString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("open", SessionOpen.String())
+	assert.Equal("closed", SessionClosed.String())
+	assert.Equal("truncated", SessionTruncated.String())
+}
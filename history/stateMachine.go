@@ -0,0 +1,172 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+var (
+	ErrIllegalTransition = errors.New("illegal state transition")
+)
+
+// TransitionTable maps an event type to the event types that are allowed to follow it.
+// Event types are case-insensitive. Event types that are neither a key nor one of the
+// allowed event types are not part of the state machine and are skipped.
+type TransitionTable map[string][]string
+
+// DefaultTransitionTable returns the transitions of a device going through its lifecycle:
+// a device comes online, becomes operational and then fully-manageable, and can announce a
+// reboot before going offline at any point, after which it must come back online.
+func DefaultTransitionTable() TransitionTable {
+	return TransitionTable{
+		interpreter.OnlineEventType:          {interpreter.OperationalEventType, interpreter.FullyManageableEventType, interpreter.RebootPendingEventType, interpreter.OfflineEventType},
+		interpreter.OperationalEventType:     {interpreter.FullyManageableEventType, interpreter.RebootPendingEventType, interpreter.OfflineEventType},
+		interpreter.FullyManageableEventType: {interpreter.RebootPendingEventType, interpreter.OfflineEventType},
+		interpreter.RebootPendingEventType:   {interpreter.OfflineEventType},
+		interpreter.OfflineEventType:         {interpreter.OnlineEventType},
+	}
+}
+
+// Allowed returns whether an event of type to is allowed to follow an event of type from.
+func (t TransitionTable) Allowed(from string, to string) bool {
+	for key, allowed := range t {
+		if !strings.EqualFold(key, from) {
+			continue
+		}
+
+		for _, eventType := range allowed {
+			if strings.EqualFold(eventType, to) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// states returns the lowercase event types that are part of the state machine.
+func (t TransitionTable) states() map[string]bool {
+	states := make(map[string]bool)
+	for from, allowed := range t {
+		states[strings.ToLower(from)] = true
+		for _, to := range allowed {
+			states[strings.ToLower(to)] = true
+		}
+	}
+
+	return states
+}
+
+// IllegalTransition is a pair of consecutive events whose event types are not allowed to follow each other.
+type IllegalTransition struct {
+	// Position is the index of the To event in the list of events sorted from oldest to newest by birthdate.
+	Position int
+	From     interpreter.Event
+	To       interpreter.Event
+}
+
+// String returns the event types and transaction uuids of the transition along with its position.
+func (t IllegalTransition) String() string {
+	fromType, _ := t.From.EventType()
+	toType, _ := t.To.EventType()
+	return fmt.Sprintf("%s (%s) -> %s (%s) at position %d", fromType, t.From.TransactionUUID, toType, t.To.TransactionUUID, t.Position)
+}
+
+// StateMachineErr is returned by StateMachineValidator and contains every illegal transition found.
+type StateMachineErr struct {
+	Transitions []IllegalTransition
+}
+
+func (e StateMachineErr) Error() string {
+	return fmt.Sprintf("%v: %d found", ErrIllegalTransition, len(e.Transitions))
+}
+
+func (e StateMachineErr) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+// Tag implements the TaggedError interface.
+func (e StateMachineErr) Tag() validation.Tag {
+	return validation.IllegalStateTransition
+}
+
+// StateMachineValidator returns a CycleValidatorFunc that walks the events from oldest to newest by birthdate
+// and validates that each event's type is allowed to follow the type of the event before it, according to the
+// transition table. Events with types that are not part of the table are skipped. It can be run on a single
+// boot cycle or on a device's whole history. If the table is empty, DefaultTransitionTable is used.
+func StateMachineValidator(table TransitionTable) CycleValidatorFunc {
+	if len(table) == 0 {
+		table = DefaultTransitionTable()
+	}
+
+	states := table.states()
+	return func(events []interpreter.Event) (bool, error) {
+		eventsCopy := make([]interpreter.Event, len(events))
+		copy(eventsCopy, events)
+		sort.SliceStable(eventsCopy, func(a, b int) bool {
+			return eventsCopy[a].Birthdate < eventsCopy[b].Birthdate
+		})
+
+		var transitions []IllegalTransition
+		var previous *interpreter.Event
+		var previousType string
+		for i := range eventsCopy {
+			eventType, err := eventsCopy[i].EventType()
+			if err != nil || !states[strings.ToLower(eventType)] {
+				continue
+			}
+
+			if previous != nil && !table.Allowed(previousType, eventType) {
+				transitions = append(transitions, IllegalTransition{
+					Position: i,
+					From:     *previous,
+					To:       eventsCopy[i],
+				})
+			}
+
+			previous = &eventsCopy[i]
+			previousType = eventType
+		}
+
+		if len(transitions) == 0 {
+			return true, nil
+		}
+
+		details := make([]string, 0, len(transitions))
+		offending := make([]interpreter.Event, 0, 2*len(transitions))
+		for _, transition := range transitions {
+			details = append(details, transition.String())
+			offending = append(offending, transition.From, transition.To)
+		}
+
+		return false, CycleValidationErr{
+			OriginalErr:       StateMachineErr{Transitions: transitions},
+			ErrorDetailKey:    "illegal transitions",
+			ErrorDetailValues: details,
+			ErrorTag:          validation.IllegalStateTransition,
+			OffendingEvents:   uniqueEvents(offending),
+		}
+	}
+}
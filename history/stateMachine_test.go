@@ -0,0 +1,166 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func TestTransitionTableAllowed(t *testing.T) {
+	table := TransitionTable{"Online": {"Operational"}}
+	tests := []struct {
+		description string
+		from        string
+		to          string
+		expected    bool
+	}{
+		{
+			description: "allowed",
+			from:        "online",
+			to:          "operational",
+			expected:    true,
+		},
+		{
+			description: "different case",
+			from:        "ONLINE",
+			to:          "operational",
+			expected:    true,
+		},
+		{
+			description: "not allowed",
+			from:        "online",
+			to:          "online",
+		},
+		{
+			description: "no transitions from event type",
+			from:        "operational",
+			to:          "online",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, table.Allowed(tc.from, tc.to))
+		})
+	}
+}
+
+func TestStateMachineValidator(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	bootTime1 := now.Add(-2 * time.Hour).Unix()
+	bootTime2 := now.Add(-1 * time.Hour).Unix()
+	tests := []struct {
+		description         string
+		table               TransitionTable
+		events              []interpreter.Event
+		expectedTransitions [][]string
+		expectedPositions   []int
+	}{
+		{
+			description: "valid history",
+			events: []interpreter.Event{
+				testCycleEvent("6", "online", bootTime2, now.Add(-50*time.Minute), "b"),
+				testCycleEvent("5", "offline", bootTime1, now.Add(-65*time.Minute), "a"),
+				testCycleEvent("4", "reboot-pending", bootTime1, now.Add(-70*time.Minute), "a"),
+				testCycleEvent("3", "fully-manageable", bootTime1, now.Add(-80*time.Minute), "a"),
+				testCycleEvent("2", "operational", bootTime1, now.Add(-90*time.Minute), "a"),
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+			},
+		},
+		{
+			description: "empty",
+		},
+		{
+			description: "repeated online",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime1, now.Add(-100*time.Minute), "b"),
+				testCycleEvent("3", "operational", bootTime1, now.Add(-90*time.Minute), "b"),
+			},
+			expectedTransitions: [][]string{{"1", "2"}},
+			expectedPositions:   []int{1},
+		},
+		{
+			description: "multiple illegal transitions",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "offline", bootTime1, now.Add(-100*time.Minute), "a"),
+				testCycleEvent("3", "operational", bootTime2, now.Add(-50*time.Minute), "b"),
+				testCycleEvent("4", "online", bootTime2, now.Add(-40*time.Minute), "b"),
+			},
+			expectedTransitions: [][]string{{"2", "3"}, {"3", "4"}},
+			expectedPositions:   []int{2, 3},
+		},
+		{
+			description: "unknown event types skipped",
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "random", bootTime1, now.Add(-100*time.Minute), "a"),
+				{TransactionUUID: "3", Destination: "non-event", Birthdate: now.Add(-95 * time.Minute).UnixNano()},
+				testCycleEvent("4", "online", bootTime1, now.Add(-90*time.Minute), "a"),
+			},
+			expectedTransitions: [][]string{{"1", "4"}},
+			expectedPositions:   []int{3},
+		},
+		{
+			description: "custom table",
+			table:       TransitionTable{"online": {"cloud-connected", "online"}, "cloud-connected": {"online"}},
+			events: []interpreter.Event{
+				testCycleEvent("1", "online", bootTime1, now.Add(-110*time.Minute), "a"),
+				testCycleEvent("2", "online", bootTime1, now.Add(-100*time.Minute), "a"),
+				testCycleEvent("3", "operational", bootTime1, now.Add(-95*time.Minute), "a"),
+				testCycleEvent("4", "cloud-connected", bootTime1, now.Add(-90*time.Minute), "a"),
+				testCycleEvent("5", "cloud-connected", bootTime1, now.Add(-80*time.Minute), "a"),
+			},
+			expectedTransitions: [][]string{{"4", "5"}},
+			expectedPositions:   []int{4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			valid, err := StateMachineValidator(tc.table).Valid(tc.events)
+			if len(tc.expectedTransitions) == 0 {
+				assert.True(valid)
+				assert.Nil(err)
+				return
+			}
+
+			assert.False(valid)
+			assert.True(errors.Is(err, ErrIllegalTransition))
+			var cvErr CycleValidationErr
+			assert.True(errors.As(err, &cvErr))
+			assert.Equal(validation.IllegalStateTransition, cvErr.Tag())
+			assert.Len(cvErr.Fields(), len(tc.expectedTransitions))
+
+			var smErr StateMachineErr
+			assert.True(errors.As(err, &smErr))
+			assert.Equal(validation.IllegalStateTransition, smErr.Tag())
+			var transitions [][]string
+			var positions []int
+			for _, transition := range smErr.Transitions {
+				transitions = append(transitions, []string{transition.From.TransactionUUID, transition.To.TransactionUUID})
+				positions = append(positions, transition.Position)
+			}
+			assert.Equal(tc.expectedTransitions, transitions)
+			assert.Equal(tc.expectedPositions, positions)
+		})
+	}
+}
+
+func TestIllegalTransitionString(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	transition := IllegalTransition{
+		Position: 2,
+		From:     testCycleEvent("1", "online", now.Unix(), now, "a"),
+		To:       testCycleEvent("2", "online", now.Unix(), now, "b"),
+	}
+	assert.Equal(t, "online (1) -> online (2) at position 2", transition.String())
+}
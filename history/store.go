@@ -0,0 +1,380 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package history
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// StoreConfig configures a Store.
+type StoreConfig struct {
+	// MaxEvents is the maximum number of events kept in the store. When it is exceeded,
+	// the oldest events by birthdate are dropped. Zero means no limit.
+	MaxEvents int
+
+	// MaxAge is how long events are kept in the store, measured from their birthdate.
+	// Zero means no limit.
+	MaxAge time.Duration
+
+	// Now returns the current time used to apply MaxAge. Defaults to time.Now.
+	Now func() time.Time
+
+	// Options configure how boot-times are grouped into cycles.
+	Options []Option
+}
+
+// Store is an in-memory history of a single device's events that is updated one event at a time.
+// It keeps the events indexed by boot-time and session id so that cycles, sessions and reboots can
+// be found without rescanning the whole history. Retention is applied whenever an event is added.
+// A Store is safe for concurrent use.
+type Store struct {
+	lock      sync.RWMutex
+	maxEvents int
+	maxAge    time.Duration
+	now       func() time.Time
+	options   options
+
+	// events are sorted from oldest to newest by birthdate.
+	events []interpreter.Event
+	ids    map[string]bool
+
+	// bootTimes are the unique valid boot-times in the store, sorted from oldest to newest.
+	bootTimes  []int64
+	byBootTime map[int64][]interpreter.Event
+	bySession  map[string][]interpreter.Event
+}
+
+// NewStore creates an empty Store.
+func NewStore(config StoreConfig) *Store {
+	now := config.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &Store{
+		maxEvents:  config.MaxEvents,
+		maxAge:     config.MaxAge,
+		now:        now,
+		options:    newOptions(config.Options),
+		ids:        make(map[string]bool),
+		byBootTime: make(map[int64][]interpreter.Event),
+		bySession:  make(map[string][]interpreter.Event),
+	}
+}
+
+// Add adds an event to the store and applies the retention limits. False is returned if the event
+// was not stored, either because an event with the same transaction uuid is already in the store
+// or because the event is older than the retention limits allow.
+func (s *Store) Add(event interpreter.Event) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(event.TransactionUUID) > 0 && s.ids[event.TransactionUUID] {
+		return false
+	}
+
+	// an event that would be dropped right away by the retention limits is not stored
+	if s.expired(event) || (s.maxEvents > 0 && len(s.events) >= s.maxEvents && event.Birthdate < s.events[0].Birthdate) {
+		return false
+	}
+
+	s.events = insertByBirthdate(s.events, event)
+	if len(event.TransactionUUID) > 0 {
+		s.ids[event.TransactionUUID] = true
+	}
+
+	if bootTime, _ := event.BootTime(); bootTime > 0 {
+		if _, found := s.byBootTime[bootTime]; !found {
+			i := sort.Search(len(s.bootTimes), func(i int) bool { return s.bootTimes[i] >= bootTime })
+			s.bootTimes = append(s.bootTimes, 0)
+			copy(s.bootTimes[i+1:], s.bootTimes[i:])
+			s.bootTimes[i] = bootTime
+		}
+		s.byBootTime[bootTime] = insertByBirthdate(s.byBootTime[bootTime], event)
+	}
+
+	if _, err := event.EventType(); err == nil && len(event.SessionID) > 0 {
+		s.bySession[event.SessionID] = insertByBirthdate(s.bySession[event.SessionID], event)
+	}
+
+	s.applyRetention()
+	return true
+}
+
+// Len returns the number of events in the store.
+func (s *Store) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.events)
+}
+
+// Events returns all of the events in the store, sorted from newest to oldest by birthdate.
+func (s *Store) Events() []interpreter.Event {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return newestToOldest(s.events)
+}
+
+// Cycles returns the boot cycles in the store, sorted from newest to oldest by boot-time.
+func (s *Store) Cycles() []Cycle {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var cycles []Cycle
+	for end := len(s.bootTimes) - 1; end >= 0; {
+		start := s.clusterStart(end)
+		cycles = append(cycles, s.cycle(start, end))
+		end = start - 1
+	}
+
+	return cycles
+}
+
+// CurrentCycle returns the newest boot cycle in the store. False is returned if the
+// store does not have any events with a valid boot-time.
+func (s *Store) CurrentCycle() (Cycle, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.latestCycle(0)
+}
+
+// LastCycle returns the boot cycle before the newest boot cycle in the store. False is returned
+// if the store has less than two boot cycles.
+func (s *Store) LastCycle() (Cycle, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.latestCycle(1)
+}
+
+// Cycle returns the boot cycle containing the boot-time passed in. False is returned if no
+// event in the store has that boot-time.
+func (s *Store) Cycle(bootTime int64) (Cycle, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	i := sort.Search(len(s.bootTimes), func(i int) bool { return s.bootTimes[i] >= bootTime })
+	if i == len(s.bootTimes) || s.bootTimes[i] != bootTime {
+		return Cycle{}, false
+	}
+
+	return s.cycle(s.clusterStart(i), s.clusterEnd(i)), true
+}
+
+// Reboot returns the latest reboot in the store, which is the transition from the last cycle
+// to the current cycle. False is returned if the store has less than two boot cycles.
+func (s *Store) Reboot() (Reboot, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	current, found := s.latestCycle(0)
+	if !found {
+		return Reboot{}, false
+	}
+
+	last, found := s.latestCycle(1)
+	if !found {
+		return Reboot{}, false
+	}
+
+	return newReboot(last, current, s.options), true
+}
+
+// Sessions returns the sessions in the store, sorted from oldest to newest by the birthdate
+// of their first event.
+func (s *Store) Sessions() []Session {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	sessions := make([]Session, 0, len(s.bySession))
+	for id, events := range s.bySession {
+		sessions = append(sessions, newSession(id, copyEvents(events), s.options))
+	}
+
+	sort.Slice(sessions, func(a, b int) bool {
+		return sessions[a].Events[0].Birthdate < sessions[b].Events[0].Birthdate
+	})
+
+	return sessions
+}
+
+// Session returns the session with the id passed in. False is returned if the
+// store does not have any events from the session.
+func (s *Store) Session(id string) (Session, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	events, found := s.bySession[id]
+	if !found {
+		return Session{}, false
+	}
+
+	return newSession(id, copyEvents(events), s.options), true
+}
+
+// Parse runs the parser with the history relevant to the current event. Only the events from the cycle
+// before the current event's cycle onwards are passed to the parser, so the result is the same as parsing
+// the whole history for every parser in this package without scanning it.
+func (s *Store) Parse(parser EventsParser, currentEvent interpreter.Event) ([]interpreter.Event, error) {
+	return parser.Parse(s.relevantEvents(currentEvent), currentEvent)
+}
+
+// Find runs the finder with the history relevant to the current event, in the same way as Parse.
+func (s *Store) Find(finder EventFinder, currentEvent interpreter.Event) (interpreter.Event, error) {
+	return finder.Find(s.relevantEvents(currentEvent), currentEvent)
+}
+
+// relevantEvents returns the events belonging to the cycle before the current event's cycle and every
+// cycle after it, sorted from newest to oldest by birthdate.
+func (s *Store) relevantEvents(currentEvent interpreter.Event) []interpreter.Event {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	bootTime, _ := currentEvent.BootTime()
+	if bootTime <= 0 {
+		return nil
+	}
+
+	// find the newest boot-time older than the current event's boot-time, then step back through
+	// that cluster and the one before it, since that cluster may be the current event's cycle.
+	start := sort.Search(len(s.bootTimes), func(i int) bool { return s.bootTimes[i] >= bootTime }) - 1
+	for clusters := 0; clusters < 2 && start >= 0; clusters++ {
+		start = s.clusterStart(start) - 1
+	}
+
+	var events []interpreter.Event
+	for _, bt := range s.bootTimes[start+1:] {
+		events = append(events, s.byBootTime[bt]...)
+	}
+
+	sort.SliceStable(events, birthdateDescendingSortFunc(events))
+	return events
+}
+
+// latestCycle returns the cycle that is skip cycles before the newest cycle.
+func (s *Store) latestCycle(skip int) (Cycle, bool) {
+	end := len(s.bootTimes) - 1
+	for ; skip > 0 && end >= 0; skip-- {
+		end = s.clusterStart(end) - 1
+	}
+
+	if end < 0 {
+		return Cycle{}, false
+	}
+
+	return s.cycle(s.clusterStart(end), end), true
+}
+
+// cycle creates a cycle from the boot-times between the start and end indexes, inclusive.
+func (s *Store) cycle(start int, end int) Cycle {
+	var events []interpreter.Event
+	for _, bootTime := range s.bootTimes[start : end+1] {
+		events = append(events, s.byBootTime[bootTime]...)
+	}
+
+	return newCycle(s.bootTimes[start], events)
+}
+
+// clusterStart returns the index of the earliest boot-time in the same cluster as the boot-time at index i.
+func (s *Store) clusterStart(i int) int {
+	for i > 0 && s.bootTimes[i]-s.bootTimes[i-1] <= s.options.bootTimeTolerance {
+		i--
+	}
+
+	return i
+}
+
+// clusterEnd returns the index of the latest boot-time in the same cluster as the boot-time at index i.
+func (s *Store) clusterEnd(i int) int {
+	for i < len(s.bootTimes)-1 && s.bootTimes[i+1]-s.bootTimes[i] <= s.options.bootTimeTolerance {
+		i++
+	}
+
+	return i
+}
+
+func (s *Store) expired(event interpreter.Event) bool {
+	return s.maxAge > 0 && event.Birthdate < s.now().Add(-1*s.maxAge).UnixNano()
+}
+
+// applyRetention drops the oldest events until the store is within its limits.
+func (s *Store) applyRetention() {
+	for len(s.events) > 0 && ((s.maxEvents > 0 && len(s.events) > s.maxEvents) || s.expired(s.events[0])) {
+		s.remove(s.events[0])
+		s.events = s.events[1:]
+	}
+}
+
+// remove removes an event from the indexes.
+func (s *Store) remove(event interpreter.Event) {
+	delete(s.ids, event.TransactionUUID)
+
+	if bootTime, _ := event.BootTime(); bootTime > 0 {
+		s.byBootTime[bootTime] = removeEvent(s.byBootTime[bootTime], event)
+		if len(s.byBootTime[bootTime]) == 0 {
+			delete(s.byBootTime, bootTime)
+			i := sort.Search(len(s.bootTimes), func(i int) bool { return s.bootTimes[i] >= bootTime })
+			s.bootTimes = append(s.bootTimes[:i], s.bootTimes[i+1:]...)
+		}
+	}
+
+	if events, found := s.bySession[event.SessionID]; found {
+		s.bySession[event.SessionID] = removeEvent(events, event)
+		if len(s.bySession[event.SessionID]) == 0 {
+			delete(s.bySession, event.SessionID)
+		}
+	}
+}
+
+// insertByBirthdate inserts an event into a list of events sorted from oldest to newest by birthdate,
+// after any events with the same birthdate.
+func insertByBirthdate(events []interpreter.Event, event interpreter.Event) []interpreter.Event {
+	i := sort.Search(len(events), func(i int) bool { return events[i].Birthdate > event.Birthdate })
+	events = append(events, interpreter.Event{})
+	copy(events[i+1:], events[i:])
+	events[i] = event
+	return events
+}
+
+func removeEvent(events []interpreter.Event, event interpreter.Event) []interpreter.Event {
+	for i, e := range events {
+		if sameEvent(e, event) {
+			return append(events[:i], events[i+1:]...)
+		}
+	}
+
+	return events
+}
+
+func copyEvents(events []interpreter.Event) []interpreter.Event {
+	eventsCopy := make([]interpreter.Event, len(events))
+	copy(eventsCopy, events)
+	return eventsCopy
+}
+
+func newestToOldest(events []interpreter.Event) []interpreter.Event {
+	sorted := make([]interpreter.Event, len(events))
+	for i, event := range events {
+		sorted[len(events)-1-i] = event
+	}
+
+	return sorted
+}
@@ -0,0 +1,243 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+	"github.com/xmidt-org/interpreter/validation"
+)
+
+func testStoreEvents(now time.Time) []interpreter.Event {
+	bootTime1 := now.Add(-3 * time.Hour)
+	bootTime2 := now.Add(-2 * time.Hour)
+	bootTime3 := now.Add(-1 * time.Hour)
+	return []interpreter.Event{
+		testCycleEvent("1", "online", bootTime1.Unix(), bootTime1.Add(time.Minute), "a"),
+		testCycleEvent("2", "reboot-pending", bootTime1.Unix(), bootTime2.Add(-3*time.Minute), "a"),
+		testCycleEvent("3", "offline", bootTime1.Unix()+1, bootTime2.Add(-2*time.Minute), "a"),
+		testCycleEvent("4", "online", bootTime2.Unix(), bootTime2.Add(time.Minute), "b"),
+		testCycleEvent("5", "operational", bootTime2.Unix(), bootTime2.Add(2*time.Minute), "b"),
+		testCycleEvent("6", "offline", bootTime2.Unix(), bootTime3.Add(-1*time.Minute), "b"),
+		testCycleEvent("7", "online", bootTime3.Unix(), bootTime3.Add(time.Minute), "c"),
+		testCycleEvent("8", "fully-manageable", bootTime3.Unix()+2, bootTime3.Add(2*time.Minute), "c"),
+		interpreter.Event{TransactionUUID: "no-boot-time", Birthdate: bootTime3.Add(3 * time.Minute).UnixNano()},
+	}
+}
+
+func TestStoreAdd(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	events := testStoreEvents(now)
+	store := NewStore(StoreConfig{})
+
+	// add the events out of order
+	for i := len(events) - 1; i >= 0; i-- {
+		assert.True(store.Add(events[i]))
+	}
+
+	assert.False(store.Add(events[0]))
+	assert.Equal(len(events), store.Len())
+	assert.Equal([]string{"no-boot-time", "8", "7", "6", "5", "4", "3", "2", "1"}, testEventIDs(store.Events()))
+}
+
+func TestStoreRetention(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	events := testStoreEvents(now)
+	tests := []struct {
+		description   string
+		config        StoreConfig
+		rejected      []string
+		expectedIDs   []string
+		expectedBoots int
+	}{
+		{
+			description:   "max events",
+			config:        StoreConfig{MaxEvents: 4},
+			expectedIDs:   []string{"no-boot-time", "8", "7", "6"},
+			expectedBoots: 3,
+		},
+		{
+			description:   "max age",
+			config:        StoreConfig{MaxAge: 90 * time.Minute, Now: func() time.Time { return now }},
+			rejected:      []string{"1", "2", "3", "4", "5"},
+			expectedIDs:   []string{"no-boot-time", "8", "7", "6"},
+			expectedBoots: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			store := NewStore(tc.config)
+			var rejected []string
+			for _, event := range events {
+				if !store.Add(event) {
+					rejected = append(rejected, event.TransactionUUID)
+				}
+			}
+
+			assert.Equal(tc.rejected, rejected)
+			assert.Equal(tc.expectedIDs, testEventIDs(store.Events()))
+			assert.Len(store.Cycles(), tc.expectedBoots)
+
+			// older events are not stored once the store is full
+			assert.False(store.Add(events[0]))
+		})
+	}
+}
+
+func TestStoreCycles(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	events := testStoreEvents(now)
+	tests := []struct {
+		description string
+		opts        []Option
+	}{
+		{
+			description: "no tolerance",
+		},
+		{
+			description: "with tolerance",
+			opts:        []Option{WithBootTimeTolerance(2 * time.Second)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			store := NewStore(StoreConfig{Options: tc.opts})
+			for _, event := range events {
+				store.Add(event)
+			}
+
+			expected := SplitIntoCycles(events, tc.opts...)
+			assert.Equal(expected, store.Cycles())
+
+			current, found := store.CurrentCycle()
+			assert.True(found)
+			assert.Equal(expected[0], current)
+
+			last, found := store.LastCycle()
+			assert.True(found)
+			assert.Equal(expected[1], last)
+
+			cycle, found := store.Cycle(expected[2].BootTime)
+			assert.True(found)
+			assert.Equal(expected[2], cycle)
+
+			reboot, found := store.Reboot()
+			assert.True(found)
+			assert.Equal(Reboots(events, tc.opts...)[0], reboot)
+		})
+	}
+}
+
+func TestStoreEmpty(t *testing.T) {
+	assert := assert.New(t)
+	store := NewStore(StoreConfig{})
+	assert.Empty(store.Cycles())
+	assert.Empty(store.Sessions())
+	_, found := store.CurrentCycle()
+	assert.False(found)
+	_, found = store.LastCycle()
+	assert.False(found)
+	_, found = store.Cycle(100)
+	assert.False(found)
+	_, found = store.Reboot()
+	assert.False(found)
+	_, found = store.Session("a")
+	assert.False(found)
+
+	store.Add(testCycleEvent("1", "online", 100, time.Unix(200, 0), "a"))
+	_, found = store.LastCycle()
+	assert.False(found)
+	_, found = store.Reboot()
+	assert.False(found)
+}
+
+func TestStoreSessions(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	events := testStoreEvents(now)
+	store := NewStore(StoreConfig{})
+	for _, event := range events {
+		store.Add(event)
+	}
+
+	assert.Equal(Sessions(events), store.Sessions())
+	session, found := store.Session("b")
+	assert.True(found)
+	assert.Equal(SessionClosed, session.State())
+	assert.Equal([]string{"4", "5", "6"}, testEventIDs(session.Events))
+}
+
+func TestStoreParseAndFind(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	events := testStoreEvents(now)
+	tests := []struct {
+		description  string
+		currentEvent interpreter.Event
+		opts         []Option
+	}{
+		{
+			description:  "latest event",
+			currentEvent: events[7],
+		},
+		{
+			description:  "latest event with tolerance",
+			currentEvent: events[7],
+			opts:         []Option{WithBootTimeTolerance(2 * time.Second)},
+		},
+		{
+			description:  "older event",
+			currentEvent: events[4],
+		},
+		{
+			description:  "older event with tolerance",
+			currentEvent: events[2],
+			opts:         []Option{WithBootTimeTolerance(2 * time.Second)},
+		},
+	}
+
+	validator := validation.EventTypeValidator([]string{"online"})
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			store := NewStore(StoreConfig{Options: tc.opts})
+			for _, event := range events {
+				store.Add(event)
+			}
+
+			parsers := []EventsParserFunc{
+				RebootParser(nil, tc.opts...),
+				RebootToCurrentParser(nil, tc.opts...),
+				LastCycleParser(nil, tc.opts...),
+				CurrentCycleParser(nil, tc.opts...),
+			}
+			for _, parser := range parsers {
+				expected, expectedErr := parser.Parse(events, tc.currentEvent)
+				actual, err := store.Parse(parser, tc.currentEvent)
+				assert.Equal(testEventIDs(expected), testEventIDs(actual))
+				assert.Equal(expectedErr, err)
+			}
+
+			finders := []FinderFunc{
+				LastSessionFinder(validator, tc.opts...),
+				CurrentSessionFinder(validator, tc.opts...),
+			}
+			for _, finder := range finders {
+				expected, expectedErr := finder.Find(events, tc.currentEvent)
+				actual, err := store.Find(finder, tc.currentEvent)
+				assert.Equal(expected, actual)
+				assert.Equal(expectedErr, err)
+			}
+		})
+	}
+}
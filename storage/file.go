@@ -0,0 +1,610 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+const (
+	indexFileName     = "index.json"
+	segmentPrefix     = "segment-"
+	segmentSuffix     = ".jsonl"
+	defaultMaxSegment = 1000
+	trimChunkSize     = 4096
+)
+
+var (
+	ErrEmptyDirectory = errors.New("store directory cannot be empty")
+)
+
+// FileStoreConfig configures a FileStore.
+type FileStoreConfig struct {
+	// Dir is the directory the histories are stored in. It is created if it does not exist.
+	Dir string
+
+	// MaxSegmentEvents is the number of events written to a segment before a new segment is started.
+	// Defaults to 1000.
+	MaxSegmentEvents int
+
+	// Retention limits how much of each device's history is kept.
+	Retention Retention
+}
+
+// FileStore is an EventStore that keeps the histories on disk. Every device has its own directory
+// containing an append-only log of events split into segments, with one JSON encoded event per line,
+// and an index describing the segments. Segments that are entirely older than the retention limits are
+// skipped when reading, and Compact rewrites each history into new segments. A segment is added to the
+// index before it is written to, so segment files missing from the index are leftovers of an interrupted
+// compaction and are removed.
+type FileStore struct {
+	lock             sync.Mutex
+	dir              string
+	maxSegmentEvents int
+	retention        Retention
+	closed           bool
+}
+
+// segmentInfo describes a segment in a device's index.
+type segmentInfo struct {
+	Name string `json:"name"`
+
+	// Events is the number of events in the segment.
+	Events int `json:"events"`
+
+	// NewestBirthdate is the newest birthdate of the events in the segment.
+	NewestBirthdate int64 `json:"newestBirthdate"`
+
+	// Size is the number of bytes of complete events in the segment. A segment file of a different size
+	// was written to without the index being updated, so the stats of the segment are rebuilt from the file.
+	Size int64 `json:"size"`
+}
+
+// index describes a device's segments, in the order that they were written.
+type index struct {
+	DeviceID string        `json:"deviceID"`
+	Segments []segmentInfo `json:"segments"`
+}
+
+// NewFileStore creates a FileStore using the directory in the config.
+func NewFileStore(config FileStoreConfig) (*FileStore, error) {
+	if len(config.Dir) == 0 {
+		return nil, ErrEmptyDirectory
+	}
+
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create store directory: %w", err)
+	}
+
+	maxSegmentEvents := config.MaxSegmentEvents
+	if maxSegmentEvents <= 0 {
+		maxSegmentEvents = defaultMaxSegment
+	}
+
+	return &FileStore{
+		dir:              config.Dir,
+		maxSegmentEvents: maxSegmentEvents,
+		retention:        config.Retention,
+	}, nil
+}
+
+// Append implements the EventStore interface. Events are written to the newest segment
+// until it is full, and then to a new segment.
+func (f *FileStore) Append(deviceID string, events ...interpreter.Event) error {
+	if len(deviceID) == 0 {
+		return ErrEmptyDeviceID
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.closed {
+		return ErrClosed
+	}
+
+	deviceDir := f.deviceDir(deviceID)
+	if err := os.MkdirAll(deviceDir, 0o755); err != nil {
+		return fmt.Errorf("unable to create device directory: %w", err)
+	}
+
+	idx, err := repairIndex(deviceDir)
+	if err != nil {
+		return err
+	}
+
+	idx.DeviceID = deviceID
+	for len(events) > 0 {
+		if len(idx.Segments) == 0 || idx.Segments[len(idx.Segments)-1].Events >= f.maxSegmentEvents {
+			idx.Segments = append(idx.Segments, segmentInfo{Name: segmentName(idx.Segments)})
+			if err := writeIndex(deviceDir, idx); err != nil {
+				return err
+			}
+		}
+
+		segment := &idx.Segments[len(idx.Segments)-1]
+		count := f.maxSegmentEvents - segment.Events
+		if count > len(events) {
+			count = len(events)
+		}
+
+		size, err := appendToSegment(filepath.Join(deviceDir, segment.Name), events[:count])
+		if err != nil {
+			return err
+		}
+
+		segment.Events += count
+		segment.Size += size
+		for _, event := range events[:count] {
+			if event.Birthdate > segment.NewestBirthdate {
+				segment.NewestBirthdate = event.Birthdate
+			}
+		}
+
+		events = events[count:]
+	}
+
+	return writeIndex(deviceDir, idx)
+}
+
+// Events implements the EventStore interface.
+func (f *FileStore) Events(deviceID string) ([]interpreter.Event, error) {
+	if len(deviceID) == 0 {
+		return nil, ErrEmptyDeviceID
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.closed {
+		return nil, ErrClosed
+	}
+
+	events, err := f.readEvents(f.deviceDir(deviceID))
+	if err != nil {
+		return nil, err
+	}
+
+	return f.retention.compact(events), nil
+}
+
+// Devices implements the EventStore interface.
+func (f *FileStore) Devices() ([]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.closed {
+		return nil, ErrClosed
+	}
+
+	return f.devices()
+}
+
+// Compact implements the EventStore interface. Each device's history is rewritten into new segments
+// before the old segments are removed. Devices without any events left are removed.
+func (f *FileStore) Compact() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.closed {
+		return ErrClosed
+	}
+
+	devices, err := f.devices()
+	if err != nil {
+		return err
+	}
+
+	for _, deviceID := range devices {
+		if err := f.compactDevice(deviceID); err != nil {
+			return fmt.Errorf("unable to compact history of %s: %w", deviceID, err)
+		}
+	}
+
+	return nil
+}
+
+// Close implements the EventStore interface.
+func (f *FileStore) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *FileStore) compactDevice(deviceID string) error {
+	deviceDir := f.deviceDir(deviceID)
+	// removing the leftovers of an interrupted compaction keeps the new segments from being written after them
+	oldIdx, err := repairIndex(deviceDir)
+	if err != nil {
+		return err
+	}
+
+	events, err := f.readSegments(deviceDir, oldIdx)
+	if err != nil {
+		return err
+	}
+
+	events = f.retention.compact(events)
+	if len(events) == 0 {
+		return os.RemoveAll(deviceDir)
+	}
+
+	// write the events from oldest to newest so that appended events keep the log in order
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	newIdx := index{DeviceID: deviceID}
+	for start := 0; start < len(events); start += f.maxSegmentEvents {
+		end := start + f.maxSegmentEvents
+		if end > len(events) {
+			end = len(events)
+		}
+
+		segment := segmentInfo{
+			Name:            segmentName(oldIdx.Segments, newIdx.Segments),
+			Events:          end - start,
+			NewestBirthdate: events[end-1].Birthdate,
+		}
+
+		size, err := appendToSegment(filepath.Join(deviceDir, segment.Name), events[start:end])
+		if err != nil {
+			return err
+		}
+
+		segment.Size = size
+		newIdx.Segments = append(newIdx.Segments, segment)
+	}
+
+	if err := writeIndex(deviceDir, newIdx); err != nil {
+		return err
+	}
+
+	for _, segment := range oldIdx.Segments {
+		if err := os.Remove(filepath.Join(deviceDir, segment.Name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readEvents reads the events in a device's segments in the order that they were written, skipping
+// segments with only events older than the retention limits.
+func (f *FileStore) readEvents(deviceDir string) ([]interpreter.Event, error) {
+	idx, err := loadIndex(deviceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.readSegments(deviceDir, idx)
+}
+
+// readSegments reads the events in the segments of the index in the order that they were written,
+// skipping segments with only events older than the retention limits.
+func (f *FileStore) readSegments(deviceDir string, idx index) ([]interpreter.Event, error) {
+	cutoff := f.retention.cutoff()
+	var events []interpreter.Event
+	for _, segment := range idx.Segments {
+		if segment.NewestBirthdate < cutoff {
+			continue
+		}
+
+		segmentEvents, _, err := readSegment(filepath.Join(deviceDir, segment.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, segmentEvents...)
+	}
+
+	return events, nil
+}
+
+func (f *FileStore) devices() ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read store directory: %w", err)
+	}
+
+	var devices []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if deviceID, err := url.PathUnescape(entry.Name()); err == nil {
+			devices = append(devices, deviceID)
+		}
+	}
+
+	sort.Strings(devices)
+	return devices, nil
+}
+
+// deviceDir returns the directory of a device, named after its escaped device id. PathEscape leaves dots
+// as they are, so the ids "." and ".." are escaped further to keep them inside the store directory.
+func (f *FileStore) deviceDir(deviceID string) string {
+	name := url.PathEscape(deviceID)
+	if name == "." || name == ".." {
+		name = strings.ReplaceAll(name, ".", "%2E")
+	}
+
+	return filepath.Join(f.dir, name)
+}
+
+// segmentName returns a name for a new segment numbered after every existing segment.
+func segmentName(existing ...[]segmentInfo) string {
+	var n int
+	for _, segments := range existing {
+		for _, segment := range segments {
+			var number int
+			if _, err := fmt.Sscanf(strings.TrimPrefix(segment.Name, segmentPrefix), "%d", &number); err == nil && number >= n {
+				n = number + 1
+			}
+		}
+	}
+
+	return fmt.Sprintf("%s%06d%s", segmentPrefix, n, segmentSuffix)
+}
+
+func readIndex(deviceDir string) (index, error) {
+	var idx index
+	data, err := os.ReadFile(filepath.Join(deviceDir, indexFileName))
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+
+	if err != nil {
+		return idx, fmt.Errorf("unable to read index: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return idx, fmt.Errorf("unable to unmarshal index: %w", err)
+	}
+
+	return idx, nil
+}
+
+// loadIndex reads a device's index and rebuilds the stats of the segments whose files do not match the index,
+// which happens if the process stopped after writing events to a segment but before updating the index.
+func loadIndex(deviceDir string) (index, error) {
+	idx, err := readIndex(deviceDir)
+	if err != nil {
+		return idx, err
+	}
+
+	for i := range idx.Segments {
+		segment := &idx.Segments[i]
+		path := filepath.Join(deviceDir, segment.Name)
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) && segment.Size == 0 {
+			continue
+		}
+
+		if err == nil && info.Size() == segment.Size {
+			continue
+		}
+
+		events, size, err := readSegment(path)
+		if err != nil {
+			return idx, err
+		}
+
+		segment.Events = len(events)
+		segment.Size = size
+		segment.NewestBirthdate = 0
+		for _, event := range events {
+			if event.Birthdate > segment.NewestBirthdate {
+				segment.NewestBirthdate = event.Birthdate
+			}
+		}
+	}
+
+	return idx, nil
+}
+
+// repairIndex loads a device's index and removes the segment files that are not in it,
+// which are left behind if the process stopped in the middle of a compaction.
+func repairIndex(deviceDir string) (index, error) {
+	idx, err := loadIndex(deviceDir)
+	if err != nil {
+		return idx, err
+	}
+
+	entries, err := os.ReadDir(deviceDir)
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+
+	if err != nil {
+		return idx, fmt.Errorf("unable to read device directory: %w", err)
+	}
+
+	indexed := make(map[string]bool, len(idx.Segments))
+	for _, segment := range idx.Segments {
+		indexed[segment.Name] = true
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || indexed[name] || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(deviceDir, name)); err != nil && !os.IsNotExist(err) {
+			return idx, fmt.Errorf("unable to remove segment: %w", err)
+		}
+	}
+
+	return idx, nil
+}
+
+// writeIndex replaces a device's index by writing to a temporary file and renaming it,
+// so that the index is never partially written.
+func writeIndex(deviceDir string, idx index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("unable to marshal index: %w", err)
+	}
+
+	tmp := filepath.Join(deviceDir, indexFileName+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write index: %w", err)
+	}
+
+	if err := os.Rename(tmp, filepath.Join(deviceDir, indexFileName)); err != nil {
+		return fmt.Errorf("unable to write index: %w", err)
+	}
+
+	return nil
+}
+
+// appendToSegment writes the events to the end of a segment, returning the number of bytes written.
+func appendToSegment(path string, events []interpreter.Event) (int64, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return 0, fmt.Errorf("unable to marshal event: %w", err)
+		}
+	}
+
+	if err := trimPartialWrite(path); err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open segment: %w", err)
+	}
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return 0, fmt.Errorf("unable to write segment: %w", err)
+	}
+
+	return int64(buf.Len()), file.Close()
+}
+
+// trimPartialWrite removes a partially written last line from a segment, so that
+// new events are not appended to it.
+func trimPartialWrite(path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to open segment: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to read segment: %w", err)
+	}
+
+	// only the last byte needs to be read unless the last line is partial
+	end := info.Size()
+	if end == 0 {
+		return nil
+	}
+
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, end-1); err != nil {
+		return fmt.Errorf("unable to read segment: %w", err)
+	}
+
+	if last[0] == '\n' {
+		return nil
+	}
+
+	// scan backwards for the end of the last complete line
+	chunk := make([]byte, trimChunkSize)
+	for end > 0 {
+		start := end - trimChunkSize
+		if start < 0 {
+			start = 0
+		}
+
+		buf := chunk[:end-start]
+		if _, err := file.ReadAt(buf, start); err != nil {
+			return fmt.Errorf("unable to read segment: %w", err)
+		}
+
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			end = start + int64(i) + 1
+			break
+		}
+
+		end = start
+	}
+
+	if err := file.Truncate(end); err != nil {
+		return fmt.Errorf("unable to repair segment: %w", err)
+	}
+
+	return nil
+}
+
+// readSegment reads the events in a segment, returning them along with the number of bytes they take up.
+// A partially written last line, which can be left behind if the process stopped in the middle of an append,
+// is ignored.
+func readSegment(path string) ([]interpreter.Event, int64, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to open segment: %w", err)
+	}
+	defer file.Close()
+
+	var events []interpreter.Event
+	var size int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return events, size, nil
+		}
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to read segment: %w", err)
+		}
+
+		var event interpreter.Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, 0, fmt.Errorf("unable to unmarshal event in %s: %w", filepath.Base(path), err)
+		}
+
+		events = append(events, event)
+		size += int64(len(line))
+	}
+}
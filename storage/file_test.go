@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestNewFileStore(t *testing.T) {
+	assert := assert.New(t)
+	_, err := NewFileStore(FileStoreConfig{})
+	assert.ErrorIs(err, ErrEmptyDirectory)
+
+	dir := filepath.Join(t.TempDir(), "nested", "store")
+	store, err := NewFileStore(FileStoreConfig{Dir: dir})
+	assert.Nil(err)
+	assert.Equal(defaultMaxSegment, store.maxSegmentEvents)
+	assert.DirExists(dir)
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(FileStoreConfig{Dir: t.TempDir(), MaxSegmentEvents: 2})
+	assert.Nil(t, err)
+	testEventStore(t, store)
+}
+
+func TestFileStoreSegments(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	dir := t.TempDir()
+	retention := Retention{MaxAge: 90 * time.Minute, Now: func() time.Time { return now }}
+	store, err := NewFileStore(FileStoreConfig{Dir: dir, MaxSegmentEvents: 2, Retention: retention})
+	assert.Nil(err)
+
+	assert.Nil(store.Append("mac:112233445566", testEvent("1", now.Add(-3*time.Hour)), testEvent("2", now.Add(-2*time.Hour))))
+	assert.Nil(store.Append("mac:112233445566", testEvent("3", now.Add(-1*time.Hour))))
+	assert.Nil(store.Append("mac:112233445566", testEvent("4", now.Add(-30*time.Minute)), testEvent("5", now)))
+
+	// every test event takes up the same number of bytes
+	line, err := json.Marshal(testEvent("1", now))
+	assert.Nil(err)
+	eventSize := int64(len(line) + 1)
+
+	deviceDir := store.deviceDir("mac:112233445566")
+	idx, err := readIndex(deviceDir)
+	assert.Nil(err)
+	assert.Equal("mac:112233445566", idx.DeviceID)
+	assert.Equal([]segmentInfo{
+		{Name: "segment-000000.jsonl", Events: 2, NewestBirthdate: now.Add(-2 * time.Hour).UnixNano(), Size: 2 * eventSize},
+		{Name: "segment-000001.jsonl", Events: 2, NewestBirthdate: now.Add(-30 * time.Minute).UnixNano(), Size: 2 * eventSize},
+		{Name: "segment-000002.jsonl", Events: 1, NewestBirthdate: now.UnixNano(), Size: eventSize},
+	}, idx.Segments)
+
+	// the history survives reopening the store
+	assert.Nil(store.Close())
+	store, err = NewFileStore(FileStoreConfig{Dir: dir, MaxSegmentEvents: 2, Retention: retention})
+	assert.Nil(err)
+	events, err := store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"5", "4", "3"}, testEventIDs(events))
+
+	// compaction rewrites the history without the expired segment
+	assert.Nil(store.Compact())
+	idx, err = readIndex(deviceDir)
+	assert.Nil(err)
+	assert.Equal([]segmentInfo{
+		{Name: "segment-000003.jsonl", Events: 2, NewestBirthdate: now.Add(-30 * time.Minute).UnixNano(), Size: 2 * eventSize},
+		{Name: "segment-000004.jsonl", Events: 1, NewestBirthdate: now.UnixNano(), Size: eventSize},
+	}, idx.Segments)
+	assert.NoFileExists(filepath.Join(deviceDir, "segment-000000.jsonl"))
+
+	events, err = store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"5", "4", "3"}, testEventIDs(events))
+}
+
+func TestFileStoreCompactRemovesDevices(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	store, err := NewFileStore(FileStoreConfig{
+		Dir:       t.TempDir(),
+		Retention: Retention{MaxAge: time.Hour, Now: func() time.Time { return now }},
+	})
+	assert.Nil(err)
+	assert.Nil(store.Append("old", testEvent("1", now.Add(-2*time.Hour))))
+	assert.Nil(store.Append("new", testEvent("2", now)))
+
+	assert.Nil(store.Compact())
+	devices, err := store.Devices()
+	assert.Nil(err)
+	assert.Equal([]string{"new"}, devices)
+}
+
+func TestFileStorePartialWrite(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	store, err := NewFileStore(FileStoreConfig{Dir: t.TempDir()})
+	assert.Nil(err)
+	assert.Nil(store.Append("mac:112233445566", testEvent("1", now)))
+
+	segment := filepath.Join(store.deviceDir("mac:112233445566"), "segment-000000.jsonl")
+	file, err := os.OpenFile(segment, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.Nil(err)
+	_, err = file.WriteString(`{"transaction_uuid":"2","birth_da`)
+	assert.Nil(err)
+	assert.Nil(file.Close())
+
+	events, err := store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"1"}, testEventIDs(events))
+
+	// the partial line is removed before appending
+	assert.Nil(store.Append("mac:112233445566", testEvent("3", now.Add(time.Minute))))
+	events, err = store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"3", "1"}, testEventIDs(events))
+
+	assert.Nil(os.WriteFile(segment, []byte("not json\n"), 0o644))
+	_, err = store.Events("mac:112233445566")
+	assert.NotNil(err)
+}
+
+func TestFileStoreInterruptedWrites(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	retention := Retention{MaxAge: 90 * time.Minute, Now: func() time.Time { return now }}
+	store, err := NewFileStore(FileStoreConfig{Dir: t.TempDir(), MaxSegmentEvents: 2, Retention: retention})
+	assert.Nil(err)
+	assert.Nil(store.Append("mac:112233445566", testEvent("1", now.Add(-3*time.Hour))))
+
+	writeEvents := func(name string, events ...interpreter.Event) {
+		file, err := os.OpenFile(filepath.Join(store.deviceDir("mac:112233445566"), name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		assert.Nil(err)
+		encoder := json.NewEncoder(file)
+		for _, event := range events {
+			assert.Nil(encoder.Encode(event))
+		}
+		assert.Nil(file.Close())
+	}
+
+	// an event written to a segment without updating the index, which still has the segment as expired
+	writeEvents("segment-000000.jsonl", testEvent("2", now))
+	events, err := store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"2"}, testEventIDs(events))
+
+	// the segment is full once its stats are rebuilt
+	assert.Nil(store.Append("mac:112233445566", testEvent("3", now.Add(time.Minute))))
+	idx, err := readIndex(store.deviceDir("mac:112233445566"))
+	assert.Nil(err)
+	assert.Len(idx.Segments, 2)
+	assert.Equal(2, idx.Segments[0].Events)
+	assert.Equal(now.UnixNano(), idx.Segments[0].NewestBirthdate)
+	assert.Equal(1, idx.Segments[1].Events)
+
+	// a segment left behind by an interrupted compaction is neither read nor appended to
+	writeEvents("segment-000002.jsonl", testEvent("2", now))
+	events, err = store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"3", "2"}, testEventIDs(events))
+
+	assert.Nil(store.Append("mac:112233445566", testEvent("4", now.Add(2*time.Minute)), testEvent("5", now.Add(3*time.Minute))))
+	events, err = store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"5", "4", "3", "2"}, testEventIDs(events))
+
+	writeEvents("segment-000003.jsonl", testEvent("2", now))
+	assert.Nil(store.Compact())
+	events, err = store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"5", "4", "3", "2"}, testEventIDs(events))
+}
+
+func TestFileStoreDotDeviceIDs(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	dir := t.TempDir()
+	store, err := NewFileStore(FileStoreConfig{Dir: filepath.Join(dir, "store")})
+	assert.Nil(err)
+
+	for _, deviceID := range []string{".", ".."} {
+		assert.Equal(filepath.Join(dir, "store"), filepath.Dir(store.deviceDir(deviceID)))
+		assert.Nil(store.Append(deviceID, testEvent(deviceID, now)))
+		events, err := store.Events(deviceID)
+		assert.Nil(err)
+		assert.Equal([]string{deviceID}, testEventIDs(events))
+	}
+
+	devices, err := store.Devices()
+	assert.Nil(err)
+	assert.Equal([]string{".", ".."}, devices)
+
+	entries, err := os.ReadDir(dir)
+	assert.Nil(err)
+	assert.Len(entries, 1)
+}
+
+func TestTrimPartialWrite(t *testing.T) {
+	dir := t.TempDir()
+	longLine := strings.Repeat("a", 3*trimChunkSize)
+	tests := []struct {
+		description string
+		content     string
+		expected    string
+	}{
+		{
+			description: "empty",
+		},
+		{
+			description: "complete",
+			content:     "line 1\nline 2\n",
+			expected:    "line 1\nline 2\n",
+		},
+		{
+			description: "partial",
+			content:     "line 1\nline 2\nline",
+			expected:    "line 1\nline 2\n",
+		},
+		{
+			description: "partial longer than a chunk",
+			content:     "line 1\n" + longLine,
+			expected:    "line 1\n",
+		},
+		{
+			description: "only a partial line",
+			content:     longLine,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			path := filepath.Join(dir, fmt.Sprintf("segment-%d.jsonl", i))
+			assert.Nil(os.WriteFile(path, []byte(tc.content), 0o644))
+			assert.Nil(trimPartialWrite(path))
+			data, err := os.ReadFile(path)
+			assert.Nil(err)
+			assert.Equal(tc.expected, string(data))
+		})
+	}
+
+	assert.Nil(t, trimPartialWrite(filepath.Join(dir, "missing.jsonl")))
+	// a directory cannot be opened for writing, and the error must not be hidden
+	assert.NotNil(t, trimPartialWrite(dir))
+}
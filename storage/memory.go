@@ -0,0 +1,123 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+// MemoryStore is an EventStore that keeps the histories in memory.
+type MemoryStore struct {
+	lock      sync.RWMutex
+	retention Retention
+	histories map[string][]interpreter.Event
+	closed    bool
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore(retention Retention) *MemoryStore {
+	return &MemoryStore{
+		retention: retention,
+		histories: make(map[string][]interpreter.Event),
+	}
+}
+
+// Append implements the EventStore interface. The retention limits are applied to the device's history
+// as the events are added, so that the history does not grow without bound between compactions.
+func (m *MemoryStore) Append(deviceID string, events ...interpreter.Event) error {
+	if len(deviceID) == 0 {
+		return ErrEmptyDeviceID
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+
+	if compacted := m.retention.compact(append(m.histories[deviceID], events...)); len(compacted) > 0 {
+		m.histories[deviceID] = compacted
+	} else {
+		delete(m.histories, deviceID)
+	}
+
+	return nil
+}
+
+// Events implements the EventStore interface.
+func (m *MemoryStore) Events(deviceID string) ([]interpreter.Event, error) {
+	if len(deviceID) == 0 {
+		return nil, ErrEmptyDeviceID
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	return m.retention.compact(m.histories[deviceID]), nil
+}
+
+// Devices implements the EventStore interface.
+func (m *MemoryStore) Devices() ([]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	devices := make([]string, 0, len(m.histories))
+	for deviceID := range m.histories {
+		devices = append(devices, deviceID)
+	}
+
+	sort.Strings(devices)
+	return devices, nil
+}
+
+// Compact implements the EventStore interface. Devices without any events left are removed.
+func (m *MemoryStore) Compact() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+
+	for deviceID, events := range m.histories {
+		if compacted := m.retention.compact(events); len(compacted) > 0 {
+			m.histories[deviceID] = compacted
+		} else {
+			delete(m.histories, deviceID)
+		}
+	}
+
+	return nil
+}
+
+// Close implements the EventStore interface.
+func (m *MemoryStore) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.closed = true
+	m.histories = nil
+	return nil
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testEventStore(t, NewMemoryStore(Retention{}))
+}
+
+func TestMemoryStoreCompact(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	store := NewMemoryStore(Retention{MaxAge: time.Hour, MaxEvents: 2, Now: func() time.Time { return now }})
+
+	// the retention limits are applied as events are appended
+	assert.Nil(store.Append("old", testEvent("1", now.Add(-2*time.Hour))))
+	assert.Nil(store.Append("new", testEvent("2", now.Add(-2*time.Hour)), testEvent("3", now), testEvent("3", now)))
+	assert.Nil(store.Append("new", testEvent("4", now.Add(-time.Minute)), testEvent("5", now.Add(-2*time.Minute))))
+	devices, err := store.Devices()
+	assert.Nil(err)
+	assert.Equal([]string{"new"}, devices)
+	assert.Equal([]string{"3", "4"}, testEventIDs(store.histories["new"]))
+
+	// events expire as time passes until the store is compacted
+	now = now.Add(90 * time.Minute)
+	assert.Len(store.histories["new"], 2)
+	assert.Nil(store.Compact())
+	devices, err = store.Devices()
+	assert.Nil(err)
+	assert.Empty(devices)
+}
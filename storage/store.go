@@ -0,0 +1,108 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+var (
+	ErrEmptyDeviceID = errors.New("device id cannot be empty")
+	ErrClosed        = errors.New("store is closed")
+)
+
+// EventStore stores the event histories of devices.
+type EventStore interface {
+	// Append adds events to a device's history.
+	Append(deviceID string, events ...interpreter.Event) error
+
+	// Events returns a device's history, sorted from newest to oldest by birthdate. Events with a
+	// transaction uuid that is already in the history are dropped, as are events outside of the retention limits.
+	Events(deviceID string) ([]interpreter.Event, error)
+
+	// Devices returns the ids of all devices with a history in the store, sorted by id.
+	Devices() ([]string, error)
+
+	// Compact rewrites every device's history to drop duplicate events and events outside
+	// of the retention limits.
+	Compact() error
+
+	// Close releases the resources used by the store. The store cannot be used after it is closed.
+	Close() error
+}
+
+// Retention limits how much of a device's history is kept.
+type Retention struct {
+	// MaxEvents is the maximum number of events kept per device. Zero means no limit.
+	MaxEvents int
+
+	// MaxAge is how long events are kept, measured from their birthdate. Zero means no limit.
+	MaxAge time.Duration
+
+	// Now returns the current time used to apply MaxAge. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// cutoff returns the oldest birthdate that is kept, or zero if there is no age limit.
+func (r Retention) cutoff() int64 {
+	if r.MaxAge <= 0 {
+		return 0
+	}
+
+	now := r.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return now().Add(-1 * r.MaxAge).UnixNano()
+}
+
+// compact sorts the events from newest to oldest by birthdate, drops events with a transaction uuid
+// that has already been seen and applies the retention limits. When events share a transaction uuid,
+// the one found first in the list is kept.
+func (r Retention) compact(events []interpreter.Event) []interpreter.Event {
+	seen := make(map[string]bool)
+	cutoff := r.cutoff()
+	compacted := make([]interpreter.Event, 0, len(events))
+	for _, event := range events {
+		if len(event.TransactionUUID) > 0 && seen[event.TransactionUUID] {
+			continue
+		}
+
+		seen[event.TransactionUUID] = true
+		if event.Birthdate < cutoff {
+			continue
+		}
+
+		compacted = append(compacted, event)
+	}
+
+	sort.SliceStable(compacted, func(a, b int) bool {
+		return compacted[a].Birthdate > compacted[b].Birthdate
+	})
+
+	if r.MaxEvents > 0 && len(compacted) > r.MaxEvents {
+		compacted = compacted[:r.MaxEvents]
+	}
+
+	return compacted
+}
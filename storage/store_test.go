@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func testEvent(id string, birthdate time.Time) interpreter.Event {
+	return interpreter.Event{
+		TransactionUUID: id,
+		Destination:     "event:device-status/mac:112233445566/online",
+		Metadata:        map[string]string{interpreter.BootTimeKey: fmt.Sprint(birthdate.Add(-1 * time.Minute).Unix())},
+		Birthdate:       birthdate.UnixNano(),
+	}
+}
+
+func testEventIDs(events []interpreter.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.TransactionUUID)
+	}
+
+	return ids
+}
+
+func TestRetentionCompact(t *testing.T) {
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(t, err)
+	events := []interpreter.Event{
+		testEvent("1", now.Add(-3*time.Hour)),
+		testEvent("3", now.Add(-1*time.Hour)),
+		testEvent("2", now.Add(-2*time.Hour)),
+		testEvent("3", now.Add(-30*time.Minute)),
+		testEvent("", now.Add(-20*time.Minute)),
+		testEvent("", now.Add(-10*time.Minute)),
+	}
+
+	tests := []struct {
+		description string
+		retention   Retention
+		expectedIDs []string
+	}{
+		{
+			description: "no limits",
+			expectedIDs: []string{"", "", "3", "2", "1"},
+		},
+		{
+			description: "max events",
+			retention:   Retention{MaxEvents: 3},
+			expectedIDs: []string{"", "", "3"},
+		},
+		{
+			description: "max age",
+			retention:   Retention{MaxAge: 150 * time.Minute, Now: func() time.Time { return now }},
+			expectedIDs: []string{"", "", "3", "2"},
+		},
+		{
+			description: "both limits",
+			retention:   Retention{MaxEvents: 4, MaxAge: 90 * time.Minute, Now: func() time.Time { return now }},
+			expectedIDs: []string{"", "", "3"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			compacted := tc.retention.compact(events)
+			assert.Equal(tc.expectedIDs, testEventIDs(compacted))
+			for _, event := range compacted {
+				if event.TransactionUUID == "3" {
+					assert.Equal(now.Add(-1*time.Hour).UnixNano(), event.Birthdate)
+				}
+			}
+		})
+	}
+}
+
+// testEventStore runs the behavior shared by every EventStore.
+func testEventStore(t *testing.T, store EventStore) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+
+	assert.ErrorIs(store.Append(""), ErrEmptyDeviceID)
+	_, err = store.Events("")
+	assert.ErrorIs(err, ErrEmptyDeviceID)
+
+	assert.Nil(store.Append("mac:112233445566", testEvent("1", now.Add(-3*time.Hour)), testEvent("2", now.Add(-2*time.Hour))))
+	assert.Nil(store.Append("mac:112233445566", testEvent("3", now.Add(-1*time.Hour)), testEvent("2", now.Add(-1*time.Minute))))
+	assert.Nil(store.Append("mac:aabbccddeeff", testEvent("a", now)))
+
+	devices, err := store.Devices()
+	assert.Nil(err)
+	assert.Equal([]string{"mac:112233445566", "mac:aabbccddeeff"}, devices)
+
+	events, err := store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"3", "2", "1"}, testEventIDs(events))
+	assert.Equal(now.Add(-2*time.Hour).UnixNano(), events[1].Birthdate)
+
+	events, err = store.Events("unknown")
+	assert.Nil(err)
+	assert.Empty(events)
+
+	assert.Nil(store.Compact())
+	events, err = store.Events("mac:112233445566")
+	assert.Nil(err)
+	assert.Equal([]string{"3", "2", "1"}, testEventIDs(events))
+
+	assert.Nil(store.Close())
+	assert.ErrorIs(store.Append("mac:112233445566", testEvent("4", now)), ErrClosed)
+	_, err = store.Events("mac:112233445566")
+	assert.ErrorIs(err, ErrClosed)
+	_, err = store.Devices()
+	assert.ErrorIs(err, ErrClosed)
+	assert.ErrorIs(store.Compact(), ErrClosed)
+}
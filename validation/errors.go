@@ -42,6 +42,11 @@ type ErrorWithFields interface {
 	Fields() []string
 }
 
+// ErrorWithEvents is an optional interface for errors to implement if the error was caused by specific events.
+type ErrorWithEvents interface {
+	Events() []interpreter.Event
+}
+
 // Errors is a Multierror that also acts as an error, so that a log-friendly
 // string can be returned but each error in the list can also be accessed.
 type Errors []error
@@ -128,6 +133,30 @@ func (e Errors) UniqueTags() []Tag {
 	return tags
 }
 
+// Events implements the ErrorWithEvents interface, returning the events of every error in the list
+// that implements ErrorWithEvents.
+func (e Errors) Events() []interpreter.Event {
+	var events []interpreter.Event
+	for _, err := range e {
+		var errWithEvents ErrorWithEvents
+		if errors.As(err, &errWithEvents) {
+			events = append(events, errWithEvents.Events()...)
+		}
+	}
+
+	return events
+}
+
+// Explanation implements the Explainer interface, explaining each error in the list.
+func (e Errors) Explanation() string {
+	explained := make([]string, 0, len(e))
+	for _, err := range e {
+		explained = append(explained, ExplainError(err))
+	}
+
+	return strings.Join(explained, "\n\n")
+}
+
 // EventWithError is a type of error that connects errors with a specific event.
 type EventWithError struct {
 	Event       interpreter.Event
@@ -185,6 +214,12 @@ func (e EventWithError) UniqueTags() []Tag {
 	return nil
 }
 
+// Explanation implements the Explainer interface, explaining the underlying error
+// and including the event it is connected to.
+func (e EventWithError) Explanation() string {
+	return fmt.Sprintf("%s\n\nEvent:\n%s", ExplainError(e.OriginalErr), FormatEvents(e.Event))
+}
+
 // InvalidEventErr is a Tag Error that wraps an underlying error.
 type InvalidEventErr struct {
 	OriginalErr error
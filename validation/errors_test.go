@@ -72,6 +72,21 @@ func TestErrors(t *testing.T) {
 	}
 }
 
+func TestErrorsEvents(t *testing.T) {
+	assert := assert.New(t)
+	event1 := interpreter.Event{TransactionUUID: "1"}
+	event2 := interpreter.Event{TransactionUUID: "2"}
+	event3 := interpreter.Event{TransactionUUID: "3"}
+	errs := Errors{
+		testErrorWithEvents{events: []interpreter.Event{event1, event2}},
+		errors.New("test"),
+		fmt.Errorf("wrapped: %w", testErrorWithEvents{events: []interpreter.Event{event3}}),
+	}
+
+	assert.Equal([]interpreter.Event{event1, event2, event3}, errs.Events())
+	assert.Empty(Errors{errors.New("test")}.Events())
+}
+
 func TestError(t *testing.T) {
 	assert := assert.New(t)
 	err1 := errors.New("test err 1")
@@ -396,3 +411,12 @@ func TestEventWithError(t *testing.T) {
 		})
 	}
 }
+
+func TestEventWithErrorExplanation(t *testing.T) {
+	assert := assert.New(t)
+	event := interpreter.Event{TransactionUUID: "test-id", Destination: "event:device-status/mac:112233445566/online"}
+	err := EventWithError{Event: event, OriginalErr: testTaggedError{err: errors.New("test"), tag: FastBoot}}
+	explanation := err.Explanation()
+	assert.Contains(explanation, Explain(FastBoot).String())
+	assert.Contains(explanation, FormatEvents(event))
+}
@@ -0,0 +1,439 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/interpreter"
+)
+
+const noExplanation = "no explanation available"
+
+// Explainer is an optional interface for errors that can describe, in a human-readable
+// way, what went wrong and what to check.
+type Explainer interface {
+	Explanation() string
+}
+
+// Explanation is a long-form description of a Tag meant for the people investigating it.
+type Explanation struct {
+	Tag          Tag
+	Summary      string
+	Description  string
+	LikelyCauses []string
+	Remediation  []string
+}
+
+// String renders the explanation as multiple lines of text.
+func (e Explanation) String() string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s: %s", e.Tag, e.Summary))
+	if len(e.Description) > 0 {
+		output.WriteString("\n\n")
+		output.WriteString(e.Description)
+	}
+
+	writeList(&output, "Likely causes", e.LikelyCauses)
+	writeList(&output, "Remediation", e.Remediation)
+	return output.String()
+}
+
+var (
+	explanationsLock sync.RWMutex
+	explanations     = newExplanations()
+)
+
+func newExplanations() map[Tag]Explanation {
+	explanations := make(map[Tag]Explanation, len(builtInExplanations))
+	for _, explanation := range builtInExplanations {
+		explanations[explanation.Tag] = explanation
+	}
+
+	return explanations
+}
+
+// Explain returns the explanation for a tag. If no explanation has been
+// registered for the tag, an explanation saying so is returned.
+func Explain(tag Tag) Explanation {
+	explanationsLock.RLock()
+	defer explanationsLock.RUnlock()
+	if explanation, found := explanations[tag]; found {
+		return explanation
+	}
+
+	return Explanation{Tag: tag, Summary: noExplanation}
+}
+
+// RegisterExplanation adds or replaces the explanation for the explanation's tag.
+func RegisterExplanation(explanation Explanation) {
+	explanationsLock.Lock()
+	defer explanationsLock.Unlock()
+	explanations[explanation.Tag] = explanation
+}
+
+// ExplainError returns the explanation for an error. If the error implements Explainer,
+// its explanation is used. Otherwise, the explanations for the error's tags are returned.
+func ExplainError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var explainer Explainer
+	if errors.As(err, &explainer) {
+		return explainer.Explanation()
+	}
+
+	var taggedErrs TaggedErrors
+	var taggedErr TaggedError
+	var tags []Tag
+	if errors.As(err, &taggedErrs) {
+		tags = taggedErrs.UniqueTags()
+	} else if errors.As(err, &taggedErr) {
+		tags = []Tag{taggedErr.Tag()}
+	}
+
+	if len(tags) == 0 {
+		return err.Error()
+	}
+
+	explained := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		explained = append(explained, Explain(tag).String())
+	}
+
+	return strings.Join(explained, "\n\n")
+}
+
+// FormatEvents returns a human-readable description of events, one event per line.
+func FormatEvents(events ...interpreter.Event) string {
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		bootTime := "missing"
+		if b, err := event.BootTime(); err == nil && b > 0 {
+			bootTime = time.Unix(b, 0).UTC().Format(time.RFC3339)
+		}
+
+		lines = append(lines, fmt.Sprintf("id: %s, destination: %s, boot-time: %s, birthdate: %s",
+			event.TransactionUUID, event.Destination, bootTime, time.Unix(0, event.Birthdate).UTC().Format(time.RFC3339Nano)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func writeList(output *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+
+	output.WriteString(fmt.Sprintf("\n\n%s:", title))
+	for _, item := range items {
+		output.WriteString("\n  - ")
+		output.WriteString(item)
+	}
+}
+
+// builtInExplanations are the explanations for the tags defined in this package.
+var builtInExplanations = []Explanation{
+	{
+		Tag:         Pass,
+		Summary:     "no problems were found",
+		Description: "The event or list of events passed every validator it was run through.",
+	},
+	{
+		Tag:         MultipleTags,
+		Summary:     "more than one problem was found",
+		Description: "Multiple validators failed. Look at the individual tags to see what each problem was.",
+	},
+	{
+		Tag:          MissingDeviceID,
+		Summary:      "the device id could not be found in the destination",
+		Description:  "The event destination does not contain a device id in a known scheme (mac, uuid, dns or serial).",
+		LikelyCauses: []string{"the event was not sent by a device", "the destination is malformed"},
+		Remediation:  []string{"check the destination against the event regex", "check the service that created the event"},
+	},
+	{
+		Tag:          InconsistentDeviceID,
+		Summary:      "the event refers to more than one device id",
+		Description:  "The device ids found in the source, destination and metadata of the event are not all the same.",
+		LikelyCauses: []string{"the device is reporting a different id than the one it connected with", "the event was modified or forwarded incorrectly"},
+		Remediation:  []string{"compare the ids in the source, destination and metadata", "check whether the device hardware or its identity was replaced"},
+	},
+	{
+		Tag:          InvalidBootTime,
+		Summary:      "the boot-time is unparsable or outside of the valid time frame",
+		Description:  "The /boot-time metadata could not be parsed or is too far in the past or future to be believable.",
+		LikelyCauses: []string{"the device clock was not set when it booted", "the boot-time metadata is corrupted"},
+		Remediation:  []string{"check the device clock and NTP synchronization", "look at the raw /boot-time metadata value"},
+	},
+	{
+		Tag:          MissingBootTime,
+		Summary:      "the event does not have a boot-time",
+		Description:  "The /boot-time key is missing from the event's metadata, so the event cannot be placed in a boot cycle.",
+		LikelyCauses: []string{"older firmware that does not report boot-time", "the metadata was dropped before the event was stored"},
+		Remediation:  []string{"check the firmware version of the device", "check the services between the device and storage"},
+	},
+	{
+		Tag:          OldBootTime,
+		Summary:      "the boot-time is suspiciously old",
+		Description:  "The boot-time is older than expected but not old enough to be deemed invalid.",
+		LikelyCauses: []string{"the device has been up for a very long time", "the device clock was wrong when it booted"},
+		Remediation:  []string{"compare the boot-time with the device's uptime", "check the device clock and NTP synchronization"},
+	},
+	{
+		Tag:          NewerBootTimeFound,
+		Summary:      "another event in the history has a newer boot-time",
+		Description:  "The event being processed is not from the latest boot cycle, so it is an old event or the device's boot-times are unreliable.",
+		LikelyCauses: []string{"the event was delivered late", "the device reported a wrong boot-time"},
+		Remediation:  []string{"compare the birthdates and boot-times of both events", "check for clock resets on the device"},
+	},
+	{
+		Tag:          InvalidBootDuration,
+		Summary:      "the timestamps in the destination are not valid for the boot-time",
+		Description:  "The unix timestamps in the event destination are not in the expected range relative to the boot-time.",
+		LikelyCauses: []string{"the destination timestamps or the boot-time are wrong"},
+		Remediation:  []string{"compare the destination timestamps with the boot-time"},
+	},
+	{
+		Tag:          FastBoot,
+		Summary:      "the device booted suspiciously fast",
+		Description:  "A timestamp in the destination is closer to the boot-time than the configured minimum boot duration.",
+		LikelyCauses: []string{"the boot-time was reported late in the boot process", "the destination timestamp is wrong"},
+		Remediation:  []string{"compare the destination timestamps with the boot-time", "check how the firmware calculates boot-time"},
+	},
+	{
+		Tag:          InvalidBirthdate,
+		Summary:      "the birthdate is missing or outside of the valid time frame",
+		Description:  "The birthdate parsed from the payload is not set or is too far in the past or future.",
+		LikelyCauses: []string{"the payload does not have a ts field", "the device clock was wrong when the event was created"},
+		Remediation:  []string{"look at the ts field in the payload", "check the device clock and NTP synchronization"},
+	},
+	{
+		Tag:          MisalignedBirthdate,
+		Summary:      "the birthdate does not line up with the destination timestamps",
+		Description:  "The birthdate is further away from the timestamps in the destination than allowed.",
+		LikelyCauses: []string{"the event was created long after the timestamp in its destination", "the device clock changed"},
+		Remediation:  []string{"compare the ts field in the payload with the destination timestamps"},
+	},
+	{
+		Tag:          InvalidDestination,
+		Summary:      "the destination is invalid",
+		Description:  "Something is wrong with the event destination.",
+		LikelyCauses: []string{"the destination is malformed"},
+		Remediation:  []string{"check the destination against the event regex"},
+	},
+	{
+		Tag:          NonEvent,
+		Summary:      "the message is not an event",
+		Description:  "The destination does not match the event regex, so the message is not an event.",
+		LikelyCauses: []string{"a non-event message was stored with the events"},
+		Remediation:  []string{"check the destination against the event regex"},
+	},
+	{
+		Tag:          InvalidEventType,
+		Summary:      "the event type is not one of the known event types",
+		Description:  "The event type in the destination is missing or is not in the list of valid event types.",
+		LikelyCauses: []string{"new firmware is sending a new event type", "the destination is malformed"},
+		Remediation:  []string{"add the event type to the list of valid event types if it is expected"},
+	},
+	{
+		Tag:         EventTypeMismatch,
+		Summary:     "the event type is not the type being searched for",
+		Description: "The event is valid but does not have the event type that was requested.",
+		Remediation: []string{"check the event type that was being searched for"},
+	},
+	{
+		Tag:          DuplicateEvent,
+		Summary:      "the event is a duplicate of an earlier event",
+		Description:  "Another event with the same event type and boot-time was created at the same time or earlier.",
+		LikelyCauses: []string{"the device sent the event more than once", "the event was stored twice"},
+		Remediation:  []string{"compare the duplicate events' transaction uuids and payloads"},
+	},
+	{
+		Tag:          InconsistentMetadata,
+		Summary:      "metadata values changed when they should not have",
+		Description:  "Events that should share the same values for certain metadata keys do not.",
+		LikelyCauses: []string{"a firmware upgrade or configuration change", "events from different devices in one history"},
+		Remediation:  []string{"look at the metadata keys listed and find where their values change"},
+	},
+	{
+		Tag:          RepeatedTransactionUUID,
+		Summary:      "multiple events share a transaction uuid",
+		Description:  "Every event should have a unique transaction uuid, but some are repeated.",
+		LikelyCauses: []string{"the event was stored more than once", "the device reuses transaction uuids"},
+		Remediation:  []string{"compare the events that share the uuid"},
+	},
+	{
+		Tag:          MissingOnlineEvent,
+		Summary:      "a session does not have an online event",
+		Description:  "Events were found for a session, but no online event was found for it.",
+		LikelyCauses: []string{"the online event was lost or not stored", "the session started before the history begins"},
+		Remediation:  []string{"look for the session id in the services that create online events"},
+	},
+	{
+		Tag:          MissingOfflineEvent,
+		Summary:      "a session does not have an offline event",
+		Description:  "Events were found for a session, but no offline event was found for it.",
+		LikelyCauses: []string{"the device disconnected without the offline event being stored", "the session is still connected"},
+		Remediation:  []string{"look for the session id in the services that create offline events"},
+	},
+	{
+		Tag:          InvalidEventOrder,
+		Summary:      "events did not happen in the expected order",
+		Description:  "The configured sequence of event types was not found in the list of events.",
+		LikelyCauses: []string{"an event was lost", "the device went through an unexpected boot sequence"},
+		Remediation:  []string{"compare the actual order with the expected order"},
+	},
+	{
+		Tag:          FalseReboot,
+		Summary:      "the latest online event is not the result of a reboot",
+		Description:  "The event before the latest online event has the same boot-time, so the device reconnected without rebooting.",
+		LikelyCauses: []string{"the device lost its connection and reconnected"},
+		Remediation:  []string{"check the connectivity of the device"},
+	},
+	{
+		Tag:          NoReboot,
+		Summary:      "no reboot was found",
+		Description:  "No online event was found in the list of events, so there is no reboot to examine.",
+		LikelyCauses: []string{"the online event was lost or not stored"},
+		Remediation:  []string{"check that the history contains the device's online events"},
+	},
+	{
+		Tag:          IllegalStateTransition,
+		Summary:      "an event followed an event that it is not allowed to follow",
+		Description:  "Walking the events from oldest to newest, an event type was found after an event type that the transition table does not allow it to follow, such as two online events without an offline event in between.",
+		LikelyCauses: []string{"an event was lost", "the device sent an event more than once", "the device went through an unexpected lifecycle"},
+		Remediation:  []string{"check the events around the reported positions", "check that the transition table covers every expected lifecycle"},
+	},
+	{
+		Tag:          EventGap,
+		Summary:      "too much time passed between events",
+		Description:  "Within the list of events, the time between two consecutive events, or between two event types with a configured limit such as online and operational, was longer than allowed.",
+		LikelyCauses: []string{"events were lost", "the device stopped sending events", "the device took too long to finish booting"},
+		Remediation:  []string{"check the events around the reported gaps", "check the device's connectivity during the gaps"},
+	},
+	{
+		Tag:          BootTimeRegression,
+		Summary:      "a boot-time went backwards",
+		Description:  "Walking the events from oldest to newest by birthdate, an event was found with an older boot-time than an event born before it, even though boot-times should only move forward as a device reboots.",
+		LikelyCauses: []string{"the device's clock was changed", "the event's birthdate is wrong", "the event belongs to a different device"},
+		Remediation:  []string{"compare the boot-times and birthdates of the reported events"},
+	},
+	{
+		Tag:          ClockResetBootTime,
+		Summary:      "the boot-time is a clock-reset default",
+		Description:  "The event's boot-time is from before the configured cutoff, such as a time near the unix epoch or the start of the year 2000, which devices report when their clock was reset and not yet synchronized.",
+		LikelyCauses: []string{"the device booted before synchronizing its clock", "the device's clock battery failed"},
+		Remediation:  []string{"check the device's time synchronization", "do not rely on the boot-time of the reported events"},
+	},
+	{
+		Tag:          SilentBootTimeChange,
+		Summary:      "the boot-time changed without the device reconnecting",
+		Description:  "Walking the events from oldest to newest by birthdate, the boot-time changed between two events, but the older event is not an offline event and the newer event is not an online event, so the device appears to have rebooted without disconnecting.",
+		LikelyCauses: []string{"the offline and online events were lost", "the device changed its boot-time without rebooting"},
+		Remediation:  []string{"look for the missing offline and online events around the reported events"},
+	},
+	{
+		Tag:          RebootStorm,
+		Summary:      "the device rebooted too many times within a period of time",
+		Description:  "Across the device's history, more reboots than allowed were found within the configured window.",
+		LikelyCauses: []string{"the device is crashing and restarting", "the device is losing power", "the device is stuck in a firmware upgrade loop"},
+		Remediation:  []string{"check the reboot reasons of the reported reboots", "check the device's power and firmware"},
+	},
+	{
+		Tag:          ConnectionFlapping,
+		Summary:      "the device reconnected too many times within a period of time",
+		Description:  "Across the device's history, more sessions than allowed started within the configured window without the device rebooting in between.",
+		LikelyCauses: []string{"the device's network connection is unstable", "the device is being disconnected by the server"},
+		Remediation:  []string{"check the device's network connection", "check the reasons the reported sessions ended"},
+	},
+	{
+		Tag:          SessionBootTimeMismatch,
+		Summary:      "a session has events with different boot-times",
+		Description:  "The events of a session do not share a boot-time, even though a device starts a new session every time it reboots.",
+		LikelyCauses: []string{"the device reused a session id after rebooting", "an event has the wrong boot-time"},
+		Remediation:  []string{"compare the boot-times of the session's events"},
+	},
+	{
+		Tag:          OverlappingSessions,
+		Summary:      "sessions overlap in time",
+		Description:  "A session has an event born between the first and last events of another session, even though a device is only connected through one session at a time.",
+		LikelyCauses: []string{"the device connected more than once at the same time", "an event has the wrong birthdate or session id"},
+		Remediation:  []string{"compare the birthdates of the reported sessions' events"},
+	},
+	{
+		Tag:          OfflineBeforeOnline,
+		Summary:      "a session's offline event came before its online event",
+		Description:  "Within a session, an offline event was born before the online event, even though a session has to start before it can end.",
+		LikelyCauses: []string{"an event has the wrong birthdate", "the device reused a session id"},
+		Remediation:  []string{"compare the birthdates of the session's online and offline events"},
+	},
+	{
+		Tag:          SessionStartedWhileOpen,
+		Summary:      "a session started before the previous session ended",
+		Description:  "A session's online event was born before the previous session's offline event, or the previous session has no offline event at all.",
+		LikelyCauses: []string{"the previous session's offline event was lost", "the device connected again before the server noticed it disconnected"},
+		Remediation:  []string{"look for the previous session's offline event", "check how quickly the server notices disconnects"},
+	},
+	{
+		Tag:          RebootReasonMismatch,
+		Summary:      "a reboot's reason does not match its reboot-pending events",
+		Description:  "The reason the device reported for a reboot says the reboot was planned but no reboot-pending event was sent before it, or says the reboot was unplanned even though a reboot-pending event was sent.",
+		LikelyCauses: []string{"the reboot-pending event was lost", "the device reported the wrong reboot reason", "the device crashed while waiting for a scheduled reboot"},
+		Remediation:  []string{"look for the reboot-pending event in the previous cycle", "check the reboot reason taxonomy"},
+	},
+	{
+		Tag:          RebootScheduleDeviation,
+		Summary:      "a reboot did not happen when it was scheduled",
+		Description:  "A reboot-pending event scheduled a reboot, but the following offline event or the next boot-time was too far from the scheduled time, or the device kept running past the scheduled time without rebooting.",
+		LikelyCauses: []string{"the device rebooted for another reason before the scheduled time", "the scheduled reboot was cancelled or delayed", "the offline event or the next cycle's events were lost"},
+		Remediation:  []string{"compare the schedule in the reboot-pending event's destination with the offline event and the next boot-time", "check the reboot reason of the next cycle"},
+	},
+	{
+		Tag:          IllegalMetadataChange,
+		Summary:      "a metadata value changed in a way its policy does not allow",
+		Description:  "The value of a metadata key changed when its policy does not allow it to, such as a serial number changing at all, a firmware name changing without a reboot, or a value changing to one that is not in the list of allowed transitions.",
+		LikelyCauses: []string{"events from different devices were mixed together", "the device was upgraded or reconfigured without rebooting", "the device reported the wrong metadata"},
+		Remediation:  []string{"look at the events before and after the reported changes", "check the metadata policies"},
+	},
+	{
+		Tag:          FirmwareDowngrade,
+		Summary:      "the device's firmware was downgraded",
+		Description:  "The firmware version parsed from the fw-name metadata of a boot cycle is older than the version of the boot cycle before it.",
+		LikelyCauses: []string{"the device rolled back after a failed upgrade", "the device was downgraded on purpose", "the firmware pattern for the device's model is wrong"},
+		Remediation:  []string{"check whether the previous firmware upgrade failed", "check the firmware pattern for the device's model"},
+	},
+	{
+		Tag:          SkippedFirmwareVersion,
+		Summary:      "the device skipped a mandatory firmware version",
+		Description:  "The device upgraded from a firmware version older than a mandatory version to one newer than it without running the mandatory version in between.",
+		LikelyCauses: []string{"the device was upgraded directly to a newer firmware", "the boot cycle running the mandatory version was lost"},
+		Remediation:  []string{"check the upgrade path of the device", "check the mandatory versions for the device's model"},
+	},
+	{
+		Tag:          FirmwareOutOfRange,
+		Summary:      "the device ran a firmware version outside of the range allowed for its model",
+		Description:  "The firmware version parsed from the fw-name metadata of a boot cycle is older than the minimum or newer than the maximum version allowed for the hw-model of the device.",
+		LikelyCauses: []string{"the device missed an upgrade", "the device received firmware meant for testing or for another model"},
+		Remediation:  []string{"check the firmware the device should be running", "check the allowed range for the device's model"},
+	},
+}
@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestExplain(t *testing.T) {
+	assert := assert.New(t)
+	for _, tag := range AllTags() {
+		if tag == Unknown {
+			continue
+		}
+
+		explanation := Explain(tag)
+		assert.Equal(tag, explanation.Tag)
+		assert.NotEqual(noExplanation, explanation.Summary, tag.String())
+		assert.NotEmpty(explanation.Description, tag.String())
+	}
+
+	explanation := Explain(Tag(3000))
+	assert.Equal(Tag(3000), explanation.Tag)
+	assert.Equal(noExplanation, explanation.Summary)
+}
+
+func TestRegisterExplanation(t *testing.T) {
+	assert := assert.New(t)
+	explanation := Explanation{Tag: Tag(3001), Summary: "test summary"}
+	RegisterExplanation(explanation)
+	assert.Equal(explanation, Explain(Tag(3001)))
+}
+
+func TestExplanationString(t *testing.T) {
+	tests := []struct {
+		description string
+		explanation Explanation
+		expected    string
+	}{
+		{
+			description: "summary only",
+			explanation: Explanation{Tag: FastBoot, Summary: "summary"},
+			expected:    "suspiciously_fast_boot: summary",
+		},
+		{
+			description: "all fields",
+			explanation: Explanation{
+				Tag:          FastBoot,
+				Summary:      "summary",
+				Description:  "description",
+				LikelyCauses: []string{"cause 1", "cause 2"},
+				Remediation:  []string{"fix"},
+			},
+			expected: "suspiciously_fast_boot: summary\n\ndescription\n\nLikely causes:\n  - cause 1\n  - cause 2\n\nRemediation:\n  - fix",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.explanation.String())
+		})
+	}
+}
+
+func TestExplainError(t *testing.T) {
+	tests := []struct {
+		description string
+		err         error
+		expected    string
+	}{
+		{
+			description: "nil error",
+		},
+		{
+			description: "untagged error",
+			err:         errors.New("test error"),
+			expected:    "test error",
+		},
+		{
+			description: "tagged error",
+			err:         testTaggedError{err: errors.New("test error"), tag: FastBoot},
+			expected:    Explain(FastBoot).String(),
+		},
+		{
+			description: "multiple tags",
+			err:         testTaggedErrors{err: errors.New("test error"), tags: []Tag{FastBoot, FastBoot, InvalidBirthdate}},
+			expected:    Explain(FastBoot).String() + "\n\n" + Explain(InvalidBirthdate).String(),
+		},
+		{
+			description: "explainer",
+			err:         Errors{testTaggedError{err: errors.New("test error"), tag: FastBoot}, errors.New("test error")},
+			expected:    Explain(FastBoot).String() + "\n\ntest error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ExplainError(tc.err))
+		})
+	}
+}
+
+func TestFormatEvents(t *testing.T) {
+	assert := assert.New(t)
+	now, err := time.Parse(time.RFC3339Nano, "2021-03-02T18:00:01Z")
+	assert.Nil(err)
+	events := []interpreter.Event{
+		interpreter.Event{
+			TransactionUUID: "1",
+			Destination:     "event:device-status/mac:112233445566/online",
+			Metadata:        map[string]string{interpreter.BootTimeKey: "1614708000"},
+			Birthdate:       now.UnixNano(),
+		},
+		interpreter.Event{
+			TransactionUUID: "2",
+			Destination:     "event:device-status/mac:112233445566/offline",
+			Birthdate:       now.UnixNano(),
+		},
+	}
+
+	expected := "id: 1, destination: event:device-status/mac:112233445566/online, boot-time: 2021-03-02T18:00:00Z, birthdate: 2021-03-02T18:00:01Z\n" +
+		"id: 2, destination: event:device-status/mac:112233445566/offline, boot-time: missing, birthdate: 2021-03-02T18:00:01Z"
+	assert.Equal(expected, FormatEvents(events...))
+	assert.Empty(FormatEvents())
+}
@@ -1,5 +1,7 @@
 package validation
 
+import "github.com/xmidt-org/interpreter"
+
 type testTaggedError struct {
 	err error
 	tag Tag
@@ -50,3 +52,15 @@ func (t testTaggedErrors) UniqueTags() []Tag {
 func (t testTaggedErrors) Unwrap() error {
 	return t.err
 }
+
+type testErrorWithEvents struct {
+	events []interpreter.Event
+}
+
+func (t testErrorWithEvents) Error() string {
+	return "test error with events"
+}
+
+func (t testErrorWithEvents) Events() []interpreter.Event {
+	return t.events
+}
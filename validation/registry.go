@@ -0,0 +1,311 @@
+/**
+ * Copyright 2021 Comcast Cable Communications Management, LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrEmptyValidatorName     = errors.New("validator name is empty")
+	ErrDuplicateValidatorName = errors.New("validator name already registered")
+	ErrNilFactory             = errors.New("validator factory is nil")
+	ErrValidatorNotFound      = errors.New("validator not found")
+)
+
+// ConfigField describes a single configuration option accepted by a registered validator.
+type ConfigField struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Descriptor describes a registered validator: its name, what it does, the tags it can produce
+// and the configuration it accepts.
+type Descriptor struct {
+	Name        string
+	Description string
+	Tags        []Tag
+	Config      []ConfigField
+
+	// WholeHistory is true for cycle validators that are meant to be run on a device's whole history
+	// rather than on each boot cycle, because they compare boot cycles with each other or follow sessions across them.
+	WholeHistory bool
+}
+
+// ConfigDecoder decodes a validator's configuration into the value passed in.
+type ConfigDecoder func(interface{}) error
+
+// Decode runs the ConfigDecoder. A nil ConfigDecoder leaves the value untouched,
+// so validators are created with their zero-value configuration.
+func (d ConfigDecoder) Decode(v interface{}) error {
+	if d == nil {
+		return nil
+	}
+
+	return d(v)
+}
+
+// Factory creates a Validator from its configuration.
+type Factory func(decode ConfigDecoder) (Validator, error)
+
+type registryEntry struct {
+	descriptor Descriptor
+	factory    Factory
+}
+
+// Registry holds named Validator factories along with their descriptions.
+type Registry struct {
+	lock    sync.RWMutex
+	entries map[string]registryEntry
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]registryEntry),
+	}
+}
+
+// Register adds a validator to the registry. The descriptor's name must be
+// non-empty and unique within the registry.
+func (r *Registry) Register(descriptor Descriptor, factory Factory) error {
+	if len(descriptor.Name) == 0 {
+		return ErrEmptyValidatorName
+	}
+
+	if factory == nil {
+		return ErrNilFactory
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, found := r.entries[descriptor.Name]; found {
+		return fmt.Errorf("%w: %s", ErrDuplicateValidatorName, descriptor.Name)
+	}
+
+	r.entries[descriptor.Name] = registryEntry{descriptor: descriptor, factory: factory}
+	return nil
+}
+
+// New creates the validator registered under name, using decode to read its configuration.
+func (r *Registry) New(name string, decode ConfigDecoder) (Validator, error) {
+	r.lock.RLock()
+	entry, found := r.entries[name]
+	r.lock.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrValidatorNotFound, name)
+	}
+
+	return entry.factory(decode)
+}
+
+// Descriptor returns the descriptor of the validator registered under name.
+func (r *Registry) Descriptor(name string) (Descriptor, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	entry, found := r.entries[name]
+	return entry.descriptor, found
+}
+
+// Descriptors returns the descriptors of all registered validators, sorted by name.
+func (r *Registry) Descriptors() []Descriptor {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	descriptors := make([]Descriptor, 0, len(r.entries))
+	for _, entry := range r.entries {
+		descriptors = append(descriptors, entry.descriptor)
+	}
+
+	sort.Slice(descriptors, func(a, b int) bool {
+		return descriptors[a].Name < descriptors[b].Name
+	})
+
+	return descriptors
+}
+
+// TimeValidatorConfig is the configuration used by registered validators that
+// need a TimeValidator. The current time is always time.Now.
+type TimeValidatorConfig struct {
+	ValidFrom    time.Duration
+	ValidTo      time.Duration
+	MinValidYear int
+	MaxValidYear int
+}
+
+// TimeValidator creates a TimeValidator from the config.
+func (c TimeValidatorConfig) TimeValidator() TimeValidator {
+	return TimeValidator{
+		Current:      time.Now,
+		ValidFrom:    c.ValidFrom,
+		ValidTo:      c.ValidTo,
+		MinValidYear: c.MinValidYear,
+		MaxValidYear: c.MaxValidYear,
+	}
+}
+
+var (
+	timeValidatorConfigFields = []ConfigField{
+		{Name: "validFrom", Type: "duration", Description: "how far in the past a time may be"},
+		{Name: "validTo", Type: "duration", Description: "how far in the future a time may be"},
+		{Name: "minValidYear", Type: "int", Description: "earliest year allowed"},
+		{Name: "maxValidYear", Type: "int", Description: "latest year allowed"},
+	}
+
+	defaultRegistry = newDefaultRegistry()
+)
+
+// DefaultRegistry returns the registry that contains all of the built-in validators.
+// Validators registered with Register are added to this registry.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a validator to the default registry.
+func Register(descriptor Descriptor, factory Factory) error {
+	return defaultRegistry.Register(descriptor, factory)
+}
+
+func newDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, entry := range builtInValidators {
+		// built-in names are unique, so registration cannot fail
+		registry.Register(entry.descriptor, entry.factory)
+	}
+
+	return registry
+}
+
+// builtInValidators are the validators found in the default registry.
+var builtInValidators = []registryEntry{
+	{
+		descriptor: Descriptor{
+			Name:        "boot_time",
+			Description: "validates that the boot-time exists, is parsable and falls within the configured time frame",
+			Tags:        []Tag{MissingBootTime, InvalidBootTime, OldBootTime},
+			Config:      timeValidatorConfigFields,
+		},
+		factory: func(decode ConfigDecoder) (Validator, error) {
+			var config TimeValidatorConfig
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return BootTimeValidator(config.TimeValidator()), nil
+		},
+	},
+	{
+		descriptor: Descriptor{
+			Name:        "birthdate",
+			Description: "validates that the birthdate is set and falls within the configured time frame",
+			Tags:        []Tag{InvalidBirthdate},
+			Config:      timeValidatorConfigFields,
+		},
+		factory: func(decode ConfigDecoder) (Validator, error) {
+			var config TimeValidatorConfig
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return BirthdateValidator(config.TimeValidator()), nil
+		},
+	},
+	{
+		descriptor: Descriptor{
+			Name:        "birthdate_alignment",
+			Description: "validates that the birthdate is close to the timestamps in the destination",
+			Tags:        []Tag{MisalignedBirthdate},
+			Config: []ConfigField{
+				{Name: "maxDuration", Type: "duration", Description: "maximum distance between the birthdate and a destination timestamp"},
+			},
+		},
+		factory: func(decode ConfigDecoder) (Validator, error) {
+			var config struct{ MaxDuration time.Duration }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return BirthdateAlignmentValidator(config.MaxDuration), nil
+		},
+	},
+	{
+		descriptor: Descriptor{
+			Name:        "destination",
+			Description: "validates that the destination is an event of the configured type",
+			Tags:        []Tag{NonEvent, EventTypeMismatch},
+			Config: []ConfigField{
+				{Name: "eventType", Type: "string", Description: "event type the destination must have"},
+			},
+		},
+		factory: func(decode ConfigDecoder) (Validator, error) {
+			var config struct{ EventType string }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return DestinationValidator(config.EventType), nil
+		},
+	},
+	{
+		descriptor: Descriptor{
+			Name:        "consistent_device_id",
+			Description: "validates that every device id in the source, destination and metadata is the same",
+			Tags:        []Tag{InconsistentDeviceID},
+		},
+		factory: func(_ ConfigDecoder) (Validator, error) {
+			return ConsistentDeviceIDValidator(), nil
+		},
+	},
+	{
+		descriptor: Descriptor{
+			Name:        "boot_duration",
+			Description: "validates that the destination timestamps are not suspiciously close to the boot-time",
+			Tags:        []Tag{MissingBootTime, InvalidBootTime, FastBoot},
+			Config: []ConfigField{
+				{Name: "minDuration", Type: "duration", Description: "minimum time between the boot-time and a destination timestamp"},
+			},
+		},
+		factory: func(decode ConfigDecoder) (Validator, error) {
+			var config struct{ MinDuration time.Duration }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			return BootDurationValidator(config.MinDuration), nil
+		},
+	},
+	{
+		descriptor: Descriptor{
+			Name:        "event_type",
+			Description: "validates that the event type is one of the configured event types, or is in the event type registry",
+			Tags:        []Tag{InvalidEventType},
+			Config: []ConfigField{
+				{Name: "eventTypes", Type: "[]string", Description: "event types that are allowed; defaults to the registered event types"},
+			},
+		},
+		factory: func(decode ConfigDecoder) (Validator, error) {
+			var config struct{ EventTypes []string }
+			if err := decode.Decode(&config); err != nil {
+				return nil, err
+			}
+			if len(config.EventTypes) == 0 {
+				return EventTypeRegistryValidator(nil), nil
+			}
+			return EventTypeValidator(config.EventTypes), nil
+		},
+	},
+}
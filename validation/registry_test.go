@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/interpreter"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	factory := func(_ ConfigDecoder) (Validator, error) {
+		return DefaultValidator(), nil
+	}
+
+	tests := []struct {
+		description string
+		descriptor  Descriptor
+		factory     Factory
+		expectedErr error
+	}{
+		{
+			description: "success",
+			descriptor:  Descriptor{Name: "test"},
+			factory:     factory,
+		},
+		{
+			description: "empty name",
+			factory:     factory,
+			expectedErr: ErrEmptyValidatorName,
+		},
+		{
+			description: "nil factory",
+			descriptor:  Descriptor{Name: "test"},
+			expectedErr: ErrNilFactory,
+		},
+		{
+			description: "duplicate name",
+			descriptor:  Descriptor{Name: "existing"},
+			factory:     factory,
+			expectedErr: ErrDuplicateValidatorName,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			registry := NewRegistry()
+			assert.Nil(registry.Register(Descriptor{Name: "existing"}, factory))
+			err := registry.Register(tc.descriptor, tc.factory)
+			assert.True(errors.Is(err, tc.expectedErr))
+			if tc.expectedErr == nil {
+				descriptor, found := registry.Descriptor(tc.descriptor.Name)
+				assert.True(found)
+				assert.Equal(tc.descriptor, descriptor)
+			}
+		})
+	}
+}
+
+func TestRegistryNew(t *testing.T) {
+	assert := assert.New(t)
+	registry := NewRegistry()
+	decodeErr := errors.New("decode error")
+	registry.Register(Descriptor{Name: "event_type"}, func(decode ConfigDecoder) (Validator, error) {
+		var config struct{ EventTypes []string }
+		if err := decode.Decode(&config); err != nil {
+			return nil, err
+		}
+		return EventTypeValidator(config.EventTypes), nil
+	})
+
+	validator, err := registry.New("event_type", func(v interface{}) error {
+		v.(*struct{ EventTypes []string }).EventTypes = []string{"online"}
+		return nil
+	})
+	assert.Nil(err)
+	valid, _ := validator.Valid(interpreter.Event{Destination: "event:device-status/mac:112233445566/online"})
+	assert.True(valid)
+
+	validator, err = registry.New("event_type", nil)
+	assert.Nil(err)
+	valid, _ = validator.Valid(interpreter.Event{Destination: "event:device-status/mac:112233445566/online"})
+	assert.False(valid)
+
+	_, err = registry.New("event_type", func(_ interface{}) error { return decodeErr })
+	assert.Equal(decodeErr, err)
+
+	_, err = registry.New("random", nil)
+	assert.True(errors.Is(err, ErrValidatorNotFound))
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	assert := assert.New(t)
+	registry := DefaultRegistry()
+	descriptors := registry.Descriptors()
+	assert.Equal(len(builtInValidators), len(descriptors))
+	for i := 1; i < len(descriptors); i++ {
+		assert.Less(descriptors[i-1].Name, descriptors[i].Name)
+	}
+
+	for _, descriptor := range descriptors {
+		validator, err := registry.New(descriptor.Name, nil)
+		assert.Nil(err)
+		assert.NotNil(validator)
+		assert.NotEmpty(descriptor.Tags)
+	}
+
+	validator, err := registry.New("boot_time", func(v interface{}) error {
+		config := v.(*TimeValidatorConfig)
+		config.ValidFrom = -1 * time.Hour
+		config.ValidTo = time.Hour
+		return nil
+	})
+	assert.Nil(err)
+	valid, _ := validator.Valid(interpreter.Event{
+		Metadata: map[string]string{interpreter.BootTimeKey: "1"},
+	})
+	assert.False(valid)
+
+	assert.Equal(ErrEmptyValidatorName, Register(Descriptor{}, nil))
+}
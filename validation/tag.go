@@ -17,7 +17,10 @@
 
 package validation
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 // Tag is an enum used to flag the problems with an event.
 type Tag int
@@ -56,6 +59,23 @@ const (
 	InvalidEventOrder       // wrong event order
 	FalseReboot             // not a true reboot
 	NoReboot                // no reboot found
+	IllegalStateTransition  // an event type followed another event type that it is not allowed to follow
+	EventGap                // too much time passed between events
+	BootTimeRegression      // boot-time is older than the boot-time of an event born before it
+	ClockResetBootTime      // boot-time is a default reported by a device whose clock was reset
+	SilentBootTimeChange    // boot-time changed without an offline or online event in between
+	RebootStorm             // device rebooted too many times within a period of time
+	ConnectionFlapping      // device reconnected too many times within a period of time
+	SessionBootTimeMismatch // session has events with different boot-times
+	OverlappingSessions     // session has events between the first and last events of another session
+	OfflineBeforeOnline     // session has an offline event before its online event
+	SessionStartedWhileOpen // session started before the previous session ended
+	RebootReasonMismatch    // reboot reason does not match whether a reboot-pending event was sent
+	RebootScheduleDeviation // reboot did not happen at the time scheduled by its reboot-pending event
+	IllegalMetadataChange   // metadata value changed in a way its policy does not allow
+	FirmwareDowngrade       // firmware version is older than the version of the boot cycle before it
+	SkippedFirmwareVersion  // device upgraded past a mandatory firmware version without running it
+	FirmwareOutOfRange      // firmware version is outside of the range allowed for the device's model
 )
 
 const (
@@ -84,6 +104,23 @@ const (
 	InvalidEventOrderStr       = "invalid_event_order"
 	FalseRebootStr             = "false_reboot"
 	NoRebootStr                = "no_reboot"
+	IllegalStateTransitionStr  = "illegal_state_transition"
+	EventGapStr                = "event_gap"
+	BootTimeRegressionStr      = "boot_time_regression"
+	ClockResetBootTimeStr      = "clock_reset_boot_time"
+	SilentBootTimeChangeStr    = "silent_boot_time_change"
+	RebootStormStr             = "reboot_storm"
+	ConnectionFlappingStr      = "connection_flapping"
+	SessionBootTimeMismatchStr = "session_boot_time_mismatch"
+	OverlappingSessionsStr     = "overlapping_sessions"
+	OfflineBeforeOnlineStr     = "offline_before_online"
+	SessionStartedWhileOpenStr = "session_started_while_open"
+	RebootReasonMismatchStr    = "reboot_reason_mismatch"
+	RebootScheduleDeviationStr = "reboot_schedule_deviation"
+	IllegalMetadataChangeStr   = "illegal_metadata_change"
+	FirmwareDowngradeStr       = "firmware_downgrade"
+	SkippedFirmwareVersionStr  = "skipped_firmware_version"
+	FirmwareOutOfRangeStr      = "firmware_out_of_range"
 )
 
 var (
@@ -113,6 +150,23 @@ var (
 		InvalidEventOrder:       InvalidEventOrderStr,
 		FalseReboot:             FalseRebootStr,
 		NoReboot:                NoRebootStr,
+		IllegalStateTransition:  IllegalStateTransitionStr,
+		EventGap:                EventGapStr,
+		BootTimeRegression:      BootTimeRegressionStr,
+		ClockResetBootTime:      ClockResetBootTimeStr,
+		SilentBootTimeChange:    SilentBootTimeChangeStr,
+		RebootStorm:             RebootStormStr,
+		ConnectionFlapping:      ConnectionFlappingStr,
+		SessionBootTimeMismatch: SessionBootTimeMismatchStr,
+		OverlappingSessions:     OverlappingSessionsStr,
+		OfflineBeforeOnline:     OfflineBeforeOnlineStr,
+		SessionStartedWhileOpen: SessionStartedWhileOpenStr,
+		RebootReasonMismatch:    RebootReasonMismatchStr,
+		RebootScheduleDeviation: RebootScheduleDeviationStr,
+		IllegalMetadataChange:   IllegalMetadataChangeStr,
+		FirmwareDowngrade:       FirmwareDowngradeStr,
+		SkippedFirmwareVersion:  SkippedFirmwareVersionStr,
+		FirmwareOutOfRange:      FirmwareOutOfRangeStr,
 	}
 
 	stringToTag = map[string]Tag{
@@ -141,6 +195,23 @@ var (
 		InvalidEventOrderStr:       InvalidEventOrder,
 		FalseRebootStr:             FalseReboot,
 		NoRebootStr:                NoReboot,
+		IllegalStateTransitionStr:  IllegalStateTransition,
+		EventGapStr:                EventGap,
+		BootTimeRegressionStr:      BootTimeRegression,
+		ClockResetBootTimeStr:      ClockResetBootTime,
+		SilentBootTimeChangeStr:    SilentBootTimeChange,
+		RebootStormStr:             RebootStorm,
+		ConnectionFlappingStr:      ConnectionFlapping,
+		SessionBootTimeMismatchStr: SessionBootTimeMismatch,
+		OverlappingSessionsStr:     OverlappingSessions,
+		OfflineBeforeOnlineStr:     OfflineBeforeOnline,
+		SessionStartedWhileOpenStr: SessionStartedWhileOpen,
+		RebootReasonMismatchStr:    RebootReasonMismatch,
+		RebootScheduleDeviationStr: RebootScheduleDeviation,
+		IllegalMetadataChangeStr:   IllegalMetadataChange,
+		FirmwareDowngradeStr:       FirmwareDowngrade,
+		SkippedFirmwareVersionStr:  SkippedFirmwareVersion,
+		FirmwareOutOfRangeStr:      FirmwareOutOfRange,
 	}
 )
 
@@ -154,6 +225,20 @@ func ParseTag(str string) Tag {
 	return Unknown
 }
 
+// AllTags returns every known Tag, sorted by value.
+func AllTags() []Tag {
+	tags := make([]Tag, 0, len(tagToString))
+	for tag := range tagToString {
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(a, b int) bool {
+		return tags[a] < tags[b]
+	})
+
+	return tags
+}
+
 func TagsToStrings(tags []Tag) []string {
 	convertedTags := make([]string, len(tags))
 	for i, tag := range tags {
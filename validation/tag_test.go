@@ -83,3 +83,14 @@ func TestTagsToStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestAllTags(t *testing.T) {
+	assert := assert.New(t)
+	tags := AllTags()
+	assert.Equal(len(tagToString), len(tags))
+	assert.Equal(Unknown, tags[0])
+	for i := 1; i < len(tags); i++ {
+		assert.Less(tags[i-1], tags[i])
+		assert.Equal(tags[i], ParseTag(tags[i].String()))
+	}
+}
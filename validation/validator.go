@@ -263,6 +263,25 @@ func EventTypeValidator(eventTypes []string) ValidatorFunc {
 	for _, eventType := range eventTypes {
 		possibleEventTypes[eventType] = true
 	}
+	return eventTypeValidator(func(eventType string) bool {
+		return possibleEventTypes[eventType]
+	})
+}
+
+// EventTypeRegistryValidator returns a ValidatorFunc that validates that the event-type provided in the destination
+// is registered in the event type registry. If registry is nil, interpreter.DefaultEventTypeRegistry is used.
+func EventTypeRegistryValidator(registry *interpreter.EventTypeRegistry) ValidatorFunc {
+	if registry == nil {
+		registry = interpreter.DefaultEventTypeRegistry()
+	}
+
+	return eventTypeValidator(func(eventType string) bool {
+		_, found := registry.Lookup(eventType)
+		return found
+	})
+}
+
+func eventTypeValidator(allowed func(eventType string) bool) ValidatorFunc {
 	return func(e interpreter.Event) (bool, error) {
 		eventType, err := e.EventType()
 		if err != nil {
@@ -274,7 +293,7 @@ func EventTypeValidator(eventTypes []string) ValidatorFunc {
 			}
 		}
 
-		if len(eventType) == 0 || !possibleEventTypes[eventType] {
+		if len(eventType) == 0 || !allowed(eventType) {
 			return false, InvalidDestinationErr{
 				OriginalErr: ErrInvalidEventType,
 				Destination: e.Destination,
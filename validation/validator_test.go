@@ -663,6 +663,66 @@ func TestEventTypeValidator(t *testing.T) {
 	}
 }
 
+func TestEventTypeRegistryValidator(t *testing.T) {
+	registry := interpreter.NewEventTypeRegistry(
+		interpreter.EventTypeInfo{Name: "online", Roles: interpreter.SessionStartRole},
+		interpreter.EventTypeInfo{Name: "cloud-connected", Roles: interpreter.BootMilestoneRole},
+	)
+
+	tests := []struct {
+		description   string
+		registry      *interpreter.EventTypeRegistry
+		eventType     string
+		expectedValid bool
+	}{
+		{
+			description:   "registered",
+			registry:      registry,
+			eventType:     "cloud-connected",
+			expectedValid: true,
+		},
+		{
+			description:   "registered with different case",
+			registry:      registry,
+			eventType:     "Online",
+			expectedValid: true,
+		},
+		{
+			description: "not registered",
+			registry:    registry,
+			eventType:   "offline",
+		},
+		{
+			description:   "default registry",
+			eventType:     "reboot-pending",
+			expectedValid: true,
+		},
+		{
+			description: "not in default registry",
+			eventType:   "cloud-connected",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			event := interpreter.Event{
+				Destination: "event:device-status/mac:112233445566/" + tc.eventType,
+			}
+			valid, err := EventTypeRegistryValidator(tc.registry).Valid(event)
+			assert.Equal(tc.expectedValid, valid)
+			if tc.expectedValid {
+				assert.Nil(err)
+			} else {
+				var invalidTypeErr InvalidDestinationErr
+				assert.True(errors.Is(err, ErrInvalidEventType))
+				assert.True(errors.As(err, &invalidTypeErr))
+				assert.Equal(tc.eventType, invalidTypeErr.EventType)
+			}
+		})
+	}
+}
+
 func TestDeviceIDComparison(t *testing.T) {
 	tests := []struct {
 		checkID         string